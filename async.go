@@ -0,0 +1,285 @@
+package slogtool
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+)
+
+const defaultAsyncQueueSize = 1024
+
+// ErrAsyncHandlerClosed is returned when a record is handled after the [AsyncHandler] is closed.
+var ErrAsyncHandlerClosed = errors.New("async handler closed")
+
+// AsyncPolicy defines what an [AsyncHandler] does when its queue is full.
+type AsyncPolicy int
+
+const (
+	// AsyncPolicyBlock blocks the caller until there is space in the queue.
+	AsyncPolicyBlock AsyncPolicy = iota
+	// AsyncPolicyDropNewest drops the record being handled.
+	AsyncPolicyDropNewest
+	// AsyncPolicyDropOldest drops the oldest record in the queue to make space.
+	AsyncPolicyDropOldest
+	// AsyncPolicyDropBelowLevel drops the record being handled if it is below the DropLevel,
+	// otherwise blocks the caller until there is space in the queue.
+	AsyncPolicyDropBelowLevel
+)
+
+// AsyncConfig configures an [AsyncHandler].
+type AsyncConfig struct {
+	// QueueSize is the maximum number of queued records, defaults to 1024.
+	QueueSize int
+	// Policy is used when the queue is full, defaults to [AsyncPolicyBlock].
+	Policy AsyncPolicy
+	// DropLevel is the level below which records are dropped by [AsyncPolicyDropBelowLevel].
+	DropLevel slog.Level
+}
+
+// asyncItem is a queued record and the handler it is passed to.
+type asyncItem struct {
+	ctx     context.Context //nolint:containedctx // passed through to the handler.
+	handler slog.Handler
+	record  slog.Record
+}
+
+// asyncState is the queue and worker shared by an [AsyncHandler] and the handlers derived from it.
+type asyncState struct {
+	lock     sync.Mutex
+	cond     *sync.Cond
+	cfg      AsyncConfig
+	queue    []asyncItem
+	inflight int
+	closed   bool
+	done     chan struct{}
+	dropped  uint64
+	errors   uint64
+}
+
+// AsyncHandler is a [slog.Handler] that queues records and passes them to the wrapped
+// handler from a background goroutine.
+//
+// Handlers returned by WithAttrs and WithGroup share the queue, so records are handled in order.
+type AsyncHandler struct {
+	handler slog.Handler
+	state   *asyncState
+}
+
+// NewAsyncHandler returns a new AsyncHandler wrapping handler and starts its background goroutine,
+// Close should be called to stop it.
+func NewAsyncHandler(handler slog.Handler, cfg AsyncConfig) *AsyncHandler {
+	return &AsyncHandler{
+		handler: handler,
+		state:   newAsyncState(cfg),
+	}
+}
+
+func newAsyncState(cfg AsyncConfig) *asyncState {
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultAsyncQueueSize
+	}
+
+	s := &asyncState{
+		cfg:   cfg,
+		queue: make([]asyncItem, 0, cfg.QueueSize),
+		done:  make(chan struct{}),
+	}
+	s.cond = sync.NewCond(&s.lock)
+
+	go s.run()
+
+	return s
+}
+
+// Enabled reports whether the wrapped handler handles records at the given level.
+func (h *AsyncHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle queues a clone of the record, applying the queue policy if the queue is full.
+//
+// The record is handled with a context that is not cancelled when ctx is cancelled.
+func (h *AsyncHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.state.enqueue(asyncItem{
+		ctx:     context.WithoutCancel(ctx),
+		handler: h.handler,
+		record:  r.Clone(),
+	})
+}
+
+// WithAttrs returns a new AsyncHandler sharing the queue, wrapping the handler returned by the
+// wrapped handler's WithAttrs.
+func (h *AsyncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &AsyncHandler{
+		handler: h.handler.WithAttrs(attrs),
+		state:   h.state,
+	}
+}
+
+// WithGroup returns a new AsyncHandler sharing the queue, wrapping the handler returned by the
+// wrapped handler's WithGroup.
+func (h *AsyncHandler) WithGroup(name string) slog.Handler {
+	return &AsyncHandler{
+		handler: h.handler.WithGroup(name),
+		state:   h.state,
+	}
+}
+
+// Flush waits until every queued record has been handled or ctx is done.
+func (h *AsyncHandler) Flush(ctx context.Context) error {
+	return h.state.flush(ctx)
+}
+
+// Close stops accepting records and waits until every queued record has been handled or ctx is done,
+// records handled after Close are dropped.
+func (h *AsyncHandler) Close(ctx context.Context) error {
+	return h.state.close(ctx)
+}
+
+// Dropped returns the number of records dropped because the queue was full or the handler was closed.
+func (h *AsyncHandler) Dropped() uint64 {
+	h.state.lock.Lock()
+	defer h.state.lock.Unlock()
+
+	return h.state.dropped
+}
+
+// Errors returns the number of errors returned by the wrapped handler.
+func (h *AsyncHandler) Errors() uint64 {
+	h.state.lock.Lock()
+	defer h.state.lock.Unlock()
+
+	return h.state.errors
+}
+
+func (s *asyncState) enqueue(item asyncItem) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for !s.closed && len(s.queue) >= s.cfg.QueueSize {
+		switch s.cfg.Policy {
+		case AsyncPolicyDropNewest:
+			s.dropped++
+			return nil
+		case AsyncPolicyDropOldest:
+			s.queue = append(s.queue[:0], s.queue[1:]...)
+			s.dropped++
+		case AsyncPolicyDropBelowLevel:
+			if item.record.Level < s.cfg.DropLevel {
+				s.dropped++
+				return nil
+			}
+
+			s.cond.Wait()
+		case AsyncPolicyBlock:
+			s.cond.Wait()
+		}
+	}
+
+	if s.closed {
+		s.dropped++
+		return ErrAsyncHandlerClosed
+	}
+
+	s.queue = append(s.queue, item)
+	s.cond.Broadcast()
+
+	return nil
+}
+
+// run handles queued records until the state is closed and the queue is empty.
+func (s *asyncState) run() {
+	defer close(s.done)
+
+	for {
+		s.lock.Lock()
+		for len(s.queue) == 0 && !s.closed {
+			s.cond.Wait()
+		}
+
+		if len(s.queue) == 0 {
+			s.lock.Unlock()
+			return
+		}
+
+		item := s.queue[0]
+		s.queue[0] = asyncItem{}
+		s.queue = s.queue[1:]
+		s.inflight++
+		s.cond.Broadcast()
+		s.lock.Unlock()
+
+		err := item.handler.Handle(item.ctx, item.record)
+
+		s.lock.Lock()
+		s.inflight--
+		if err != nil {
+			s.errors++
+		}
+		s.cond.Broadcast()
+		s.lock.Unlock()
+	}
+}
+
+func (s *asyncState) flush(ctx context.Context) error {
+	stop := context.AfterFunc(ctx, func() {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		s.cond.Broadcast()
+	})
+	defer stop()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for len(s.queue) > 0 || s.inflight > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		s.cond.Wait()
+	}
+
+	return nil
+}
+
+func (s *asyncState) close(ctx context.Context) error {
+	s.lock.Lock()
+	s.closed = true
+	s.cond.Broadcast()
+	s.lock.Unlock()
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Flush waits until every record queued by the loggers created by the SlogManager has been handled
+// or ctx is done, it does nothing unless [WithAsync] is used.
+func (a *SlogManager) Flush(ctx context.Context) error {
+	if a.async == nil {
+		return nil
+	}
+
+	return a.async.flush(ctx)
+}
+
+// Close stops the background goroutines used by the loggers created by the SlogManager, the sampling
+// summaries (see [WithSampling]) are emitted a final time and the async queue (see [WithAsync]) is
+// stopped after every queued record has been handled or ctx is done.
+//
+// Records logged after Close are dropped if [WithAsync] is used.
+func (a *SlogManager) Close(ctx context.Context) error {
+	a.samplers.close()
+
+	if a.async == nil {
+		return nil
+	}
+
+	return a.async.close(ctx)
+}
@@ -0,0 +1,220 @@
+package slogtool_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/na4ma4/go-slogtool"
+)
+
+// gatedHandler blocks in Handle until the gate is closed, signalling started when a record arrives.
+type gatedHandler struct {
+	slog.Handler
+
+	gate    chan struct{}
+	started chan struct{}
+}
+
+func newGatedHandler(handler slog.Handler) *gatedHandler {
+	return &gatedHandler{
+		Handler: handler,
+		gate:    make(chan struct{}),
+		started: make(chan struct{}, 1),
+	}
+}
+
+func (h *gatedHandler) Handle(ctx context.Context, r slog.Record) error {
+	select {
+	case h.started <- struct{}{}:
+	default:
+	}
+
+	<-h.gate
+
+	return h.Handler.Handle(ctx, r)
+}
+
+func TestAsyncHandlerBlock(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	ctx := context.Background()
+	h := slogtool.NewAsyncHandler(slog.NewTextHandler(buf, nil), slogtool.AsyncConfig{QueueSize: 1})
+	logger := slog.New(h).With(slog.String("attr", "value"))
+
+	for i := range 5 {
+		logger.InfoContext(ctx, "msg", slog.Int("i", i+1))
+	}
+
+	if err := h.Flush(ctx); err != nil {
+		t.Fatalf("Flush returned error: %v", err)
+	}
+
+	expectLogLines(t, buf, []string{
+		"time=" + timeTestString + " level=INFO msg=msg attr=value i=1",
+		"time=" + timeTestString + " level=INFO msg=msg attr=value i=2",
+		"time=" + timeTestString + " level=INFO msg=msg attr=value i=3",
+		"time=" + timeTestString + " level=INFO msg=msg attr=value i=4",
+		"time=" + timeTestString + " level=INFO msg=msg attr=value i=5",
+	})
+
+	if err := h.Close(ctx); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	if got := h.Dropped(); got != 0 {
+		t.Errorf("Dropped: got '%d', want '0'", got)
+	}
+}
+
+func TestAsyncHandlerPolicies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cfg     slogtool.AsyncConfig
+		expect  []string
+		dropped uint64
+	}{
+		{
+			"drop-newest",
+			slogtool.AsyncConfig{QueueSize: 2, Policy: slogtool.AsyncPolicyDropNewest},
+			[]string{
+				"time=" + timeTestString + " level=INFO msg=1",
+				"time=" + timeTestString + " level=INFO msg=2",
+				"time=" + timeTestString + " level=INFO msg=3",
+			},
+			2,
+		},
+		{
+			"drop-oldest",
+			slogtool.AsyncConfig{QueueSize: 2, Policy: slogtool.AsyncPolicyDropOldest},
+			[]string{
+				"time=" + timeTestString + " level=INFO msg=1",
+				"time=" + timeTestString + " level=INFO msg=4",
+				"time=" + timeTestString + " level=WARN msg=5",
+			},
+			2,
+		},
+		{
+			"drop-below-level",
+			slogtool.AsyncConfig{QueueSize: 2, Policy: slogtool.AsyncPolicyDropBelowLevel, DropLevel: slog.LevelWarn},
+			[]string{
+				"time=" + timeTestString + " level=INFO msg=1",
+				"time=" + timeTestString + " level=INFO msg=2",
+				"time=" + timeTestString + " level=INFO msg=3",
+				"time=" + timeTestString + " level=WARN msg=5",
+			},
+			1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := bytes.NewBuffer(nil)
+			ctx := context.Background()
+			gated := newGatedHandler(slog.NewTextHandler(buf, nil))
+			h := slogtool.NewAsyncHandler(gated, tt.cfg)
+			logger := slog.New(h)
+
+			logger.InfoContext(ctx, "1")
+			<-gated.started
+
+			logger.InfoContext(ctx, "2")
+			logger.InfoContext(ctx, "3")
+			logger.InfoContext(ctx, "4")
+
+			// a warning blocks with the drop-below-level policy until there is space in the queue.
+			var wg sync.WaitGroup
+			wg.Go(func() {
+				logger.WarnContext(ctx, "5")
+			})
+
+			if tt.cfg.Policy == slogtool.AsyncPolicyDropBelowLevel {
+				close(gated.gate)
+				wg.Wait()
+			} else {
+				wg.Wait()
+				close(gated.gate)
+			}
+
+			if err := h.Close(ctx); err != nil {
+				t.Fatalf("Close returned error: %v", err)
+			}
+
+			expectLogLines(t, buf, tt.expect)
+
+			if got := h.Dropped(); got != tt.dropped {
+				t.Errorf("Dropped: got '%d', want '%d'", got, tt.dropped)
+			}
+		})
+	}
+}
+
+func TestAsyncHandlerClosed(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	ctx := context.Background()
+	h := slogtool.NewAsyncHandler(slog.NewTextHandler(buf, nil), slogtool.AsyncConfig{})
+
+	slog.New(h).InfoContext(ctx, "before")
+
+	if err := h.Close(ctx); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	err := h.Handle(ctx, slog.NewRecord(time.Now(), slog.LevelInfo, "after", 0))
+	if !errors.Is(err, slogtool.ErrAsyncHandlerClosed) {
+		t.Errorf("Handle: got error '%v', want '%v'", err, slogtool.ErrAsyncHandlerClosed)
+	}
+
+	expectLogLines(t, buf, []string{
+		"time=" + timeTestString + " level=INFO msg=before",
+	})
+
+	if got := h.Dropped(); got != 1 {
+		t.Errorf("Dropped: got '%d', want '1'", got)
+	}
+}
+
+func TestSlogManagerWithAsync(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	ctx := context.Background()
+	testLog, err := slogtool.NewSlogManager(
+		ctx,
+		slogtool.WithWriter(buf),
+		slogtool.WithAsync(slogtool.AsyncConfig{QueueSize: 4}),
+	)
+	if err != nil {
+		t.Fatalf("NewSlogManager returned error: %v", err)
+	}
+
+	server := testLog.Named("Server")
+	client := testLog.Named("Client")
+
+	server.InfoContext(ctx, "server:info")
+	client.InfoContext(ctx, "client:info")
+	server.InfoContext(ctx, "server:info2")
+
+	if err := testLog.Close(ctx); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	server.InfoContext(ctx, "server:closed")
+
+	expectLogLines(t, buf, []string{
+		"time=" + timeTestString + " level=INFO msg=server:info",
+		"time=" + timeTestString + " level=INFO msg=client:info",
+		"time=" + timeTestString + " level=INFO msg=server:info2",
+	})
+}
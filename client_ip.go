@@ -0,0 +1,162 @@
+package slogtool
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ErrInvalidTrustedProxy is returned when a trusted proxy is not a valid CIDR or IP address.
+var ErrInvalidTrustedProxy = errors.New("invalid trusted proxy")
+
+// ParseTrustedProxies parses a list of CIDRs (e.g. `10.0.0.0/8`) or IP addresses (e.g. `192.0.2.1` or `::1`)
+// for use with [LoggingOptionTrustedProxies].
+func ParseTrustedProxies(cidrs ...string) ([]netip.Prefix, error) {
+	out := make([]netip.Prefix, 0, len(cidrs))
+
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+
+		if prefix, err := netip.ParsePrefix(cidr); err == nil {
+			out = append(out, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidTrustedProxy, cidr)
+		}
+
+		addr = addr.Unmap()
+		out = append(out, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+
+	return out, nil
+}
+
+// DefaultClientIPHeader is the header the client IP is resolved from by default.
+const DefaultClientIPHeader = "X-Forwarded-For"
+
+// ResolveClientIP returns the client IP of req and the IP of the peer that sent it.
+//
+// If the peer is a trusted proxy the client IP is resolved from header by walking its hops from the
+// right past the trusted proxies, otherwise the client IP is the peer IP. Only the one header is used,
+// as a client can send any of the others through the proxy. The header can be `X-Forwarded-For` (the
+// default if empty), an RFC 7239 `Forwarded` header, or a single value header (e.g. `X-Real-IP`).
+func ResolveClientIP(req *http.Request, trusted []netip.Prefix, header string) (netip.Addr, netip.Addr) {
+	peer, ok := parseHostAddr(req.RemoteAddr)
+	if !ok || !isTrustedProxy(peer, trusted) {
+		return peer, peer
+	}
+
+	if header == "" {
+		header = DefaultClientIPHeader
+	}
+
+	var hops []string
+	if strings.EqualFold(header, "Forwarded") {
+		hops = forwardedHops(req.Header)
+	} else {
+		hops = listHeaderHops(req.Header, header)
+	}
+
+	return walkHops(peer, hops, trusted), peer
+}
+
+// walkHops returns the rightmost hop that is not a trusted proxy, the leftmost hop if every hop is
+// trusted, or the last trusted hop if a hop can not be parsed.
+func walkHops(peer netip.Addr, hops []string, trusted []netip.Prefix) netip.Addr {
+	client := peer
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, ok := parseHostAddr(hops[i])
+		if !ok {
+			return client
+		}
+
+		client = addr
+
+		if !isTrustedProxy(addr, trusted) {
+			return client
+		}
+	}
+
+	return client
+}
+
+func isTrustedProxy(addr netip.Addr, trusted []netip.Prefix) bool {
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// listHeaderHops returns the comma separated hops of all the name headers, left to right.
+func listHeaderHops(header http.Header, name string) []string {
+	var hops []string
+
+	for _, value := range header.Values(name) {
+		for hop := range strings.SplitSeq(value, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+
+	return hops
+}
+
+// forwardedHops returns the `for` parameter of each element of all the RFC 7239 `Forwarded` headers,
+// left to right, an element without a `for` parameter is returned as an empty hop.
+func forwardedHops(header http.Header) []string {
+	var hops []string
+
+	for _, value := range header.Values("Forwarded") {
+		for element := range strings.SplitSeq(value, ",") {
+			hop := ""
+
+			for pair := range strings.SplitSeq(element, ";") {
+				k, v, ok := strings.Cut(strings.TrimSpace(pair), "=")
+				if ok && strings.EqualFold(k, "for") {
+					hop = strings.Trim(v, `"`)
+				}
+			}
+
+			hops = append(hops, hop)
+		}
+	}
+
+	return hops
+}
+
+// parseHostAddr parses an IP address with an optional port, e.g. `192.0.2.1`, `192.0.2.1:8080`,
+// `2001:db8::1` or `[2001:db8::1]:8080`, IPv4-mapped IPv6 addresses are unmapped.
+func parseHostAddr(in string) (netip.Addr, bool) {
+	in = strings.TrimSpace(in)
+
+	if host, _, err := net.SplitHostPort(in); err == nil {
+		in = host
+	}
+
+	in = strings.TrimSuffix(strings.TrimPrefix(in, "["), "]")
+
+	addr, err := netip.ParseAddr(in)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+
+	return addr.Unmap(), true
+}
+
+// addrString returns the string form of addr, or an empty string if addr is the zero value.
+func addrString(addr netip.Addr) string {
+	if !addr.IsValid() {
+		return ""
+	}
+
+	return addr.String()
+}
@@ -0,0 +1,217 @@
+package slogtool_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/na4ma4/go-slogtool"
+)
+
+func mustParseTrustedProxies(t *testing.T, cidrs ...string) []netip.Prefix {
+	t.Helper()
+
+	trusted, err := slogtool.ParseTrustedProxies(cidrs...)
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies() error: %v", err)
+	}
+
+	return trusted
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	t.Parallel()
+
+	trusted := mustParseTrustedProxies(t, "10.1.2.3/8", "192.0.2.1", " 2001:db8::/32 ", "::ffff:198.51.100.1")
+
+	expect := []string{"10.0.0.0/8", "192.0.2.1/32", "2001:db8::/32", "198.51.100.1/32"}
+	for i, prefix := range trusted {
+		if prefix.String() != expect[i] {
+			t.Errorf("prefix[%d] mismatch: got=%q want=%q", i, prefix, expect[i])
+		}
+	}
+
+	if _, err := slogtool.ParseTrustedProxies("10.0.0.0/8", "proxy.example.com"); !errors.Is(err, slogtool.ErrInvalidTrustedProxy) {
+		t.Errorf("ParseTrustedProxies() error: got=%v want=%v", err, slogtool.ErrInvalidTrustedProxy)
+	}
+}
+
+func TestResolveClientIP(t *testing.T) {
+	t.Parallel()
+
+	trusted := mustParseTrustedProxies(t, "10.0.0.0/8", "2001:db8:cafe::/48")
+
+	tests := []struct {
+		name       string
+		source     string
+		remoteAddr string
+		header     http.Header
+		client     string
+		peer       string
+	}{
+		{"untrusted-peer", "", "203.0.113.1:1234", http.Header{"X-Forwarded-For": {"198.51.100.1"}}, "203.0.113.1", "203.0.113.1"},
+		{"no-headers", "", "10.0.0.1:1234", http.Header{}, "10.0.0.1", "10.0.0.1"},
+		{"xff", "", "10.0.0.1:1234", http.Header{"X-Forwarded-For": {"198.51.100.1"}}, "198.51.100.1", "10.0.0.1"},
+		{"xff-spoofed", "", "10.0.0.1:1234", http.Header{"X-Forwarded-For": {"1.2.3.4, 198.51.100.1, 10.0.0.2"}}, "198.51.100.1", "10.0.0.1"},
+		{"xff-multiple-headers", "", "10.0.0.1:1234", http.Header{"X-Forwarded-For": {"198.51.100.1", "10.0.0.2"}}, "198.51.100.1", "10.0.0.1"},
+		{"xff-all-trusted", "", "10.0.0.1:1234", http.Header{"X-Forwarded-For": {"10.0.0.3, 10.0.0.2"}}, "10.0.0.3", "10.0.0.1"},
+		{"xff-invalid-hop", "", "10.0.0.1:1234", http.Header{"X-Forwarded-For": {"198.51.100.1, garbage, 10.0.0.2"}}, "10.0.0.2", "10.0.0.1"},
+		{"xff-port", "", "10.0.0.1:1234", http.Header{"X-Forwarded-For": {"198.51.100.1:4711"}}, "198.51.100.1", "10.0.0.1"},
+		{"xff-ipv6", "", "[2001:db8:cafe::1]:1234", http.Header{"X-Forwarded-For": {"2001:db8::17"}}, "2001:db8::17", "2001:db8:cafe::1"},
+		{"xff-ipv6-port", "", "[2001:db8:cafe::1]:1234", http.Header{"X-Forwarded-For": {"[2001:db8::17]:4711"}}, "2001:db8::17", "2001:db8:cafe::1"},
+		{"xff-ipv4-mapped", "", "[::ffff:10.0.0.1]:1234", http.Header{"X-Forwarded-For": {"::ffff:198.51.100.1"}}, "198.51.100.1", "10.0.0.1"},
+		{
+			"xff-spoofed-forwarded", "", "10.0.0.1:1234",
+			http.Header{"X-Forwarded-For": {"203.0.113.9"}, "Forwarded": {"for=6.6.6.6"}},
+			"203.0.113.9", "10.0.0.1",
+		},
+		{
+			"xff-spoofed-x-real-ip", "", "10.0.0.1:1234",
+			http.Header{"X-Forwarded-For": {"203.0.113.9"}, "X-Real-Ip": {"6.6.6.6"}},
+			"203.0.113.9", "10.0.0.1",
+		},
+		{"xff-ignores-x-real-ip", "", "10.0.0.1:1234", http.Header{"X-Real-Ip": {"6.6.6.6"}}, "10.0.0.1", "10.0.0.1"},
+		{"x-real-ip", "X-Real-IP", "10.0.0.1:1234", http.Header{"X-Real-Ip": {"198.51.100.1"}}, "198.51.100.1", "10.0.0.1"},
+		{"x-real-ip-invalid", "X-Real-IP", "10.0.0.1:1234", http.Header{"X-Real-Ip": {"unknown"}}, "10.0.0.1", "10.0.0.1"},
+		{
+			"x-real-ip-ignores-xff", "X-Real-IP", "10.0.0.1:1234",
+			http.Header{"X-Real-Ip": {"198.51.100.1"}, "X-Forwarded-For": {"6.6.6.6"}},
+			"198.51.100.1", "10.0.0.1",
+		},
+		{
+			"forwarded", "Forwarded", "10.0.0.1:1234",
+			http.Header{"Forwarded": {"for=192.0.2.60;proto=http;by=203.0.113.43"}},
+			"192.0.2.60", "10.0.0.1",
+		},
+		{
+			"forwarded-ipv6", "Forwarded", "10.0.0.1:1234",
+			http.Header{"Forwarded": {`for="[2001:db8::17]:4711", for=10.0.0.2`}},
+			"2001:db8::17", "10.0.0.1",
+		},
+		{
+			"forwarded-case", "Forwarded", "10.0.0.1:1234",
+			http.Header{"Forwarded": {"For=192.0.2.60"}},
+			"192.0.2.60", "10.0.0.1",
+		},
+		{
+			"forwarded-obfuscated", "Forwarded", "10.0.0.1:1234",
+			http.Header{"Forwarded": {"for=_hidden, for=10.0.0.2"}},
+			"10.0.0.2", "10.0.0.1",
+		},
+		{
+			"forwarded-spoofed", "Forwarded", "10.0.0.1:1234",
+			http.Header{"Forwarded": {"for=6.6.6.6, for=192.0.2.60"}, "X-Forwarded-For": {"7.7.7.7"}},
+			"192.0.2.60", "10.0.0.1",
+		},
+		{"invalid-peer", "", "pipe", http.Header{"X-Forwarded-For": {"198.51.100.1"}}, "invalid IP", "invalid IP"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/test", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header = tt.header
+
+			client, peer := slogtool.ResolveClientIP(req, trusted, tt.source)
+
+			if client.String() != tt.client {
+				t.Errorf("client ip mismatch: got=%q want=%q", client, tt.client)
+			}
+
+			if peer.String() != tt.peer {
+				t.Errorf("peer ip mismatch: got=%q want=%q", peer, tt.peer)
+			}
+		})
+	}
+}
+
+func TestLoggingHTTPHandlerTrustedProxies(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	h := slogtool.LoggingHTTPHandler(
+		slog.New(slog.NewJSONHandler(buf, nil)),
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("ok"))
+		}),
+		slogtool.LoggingOptionTrustedProxies(mustParseTrustedProxies(t, "10.0.0.0/8")...),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/test", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "1.2.3.4, 198.51.100.1")
+	// sent by the client through the proxy, not trusted.
+	req.Header.Set("Forwarded", "for=6.6.6.6")
+	req.Header.Set("X-Real-IP", "6.6.6.6")
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	obj := readSingleLogObject(t, buf)
+	httpObj, _ := obj["http"].(map[string]any)
+
+	expect := map[string]string{"host": "10.0.0.1", "client_ip": "198.51.100.1", "peer_ip": "10.0.0.1"}
+	for key, want := range expect {
+		if got, _ := httpObj[key].(string); got != want {
+			t.Errorf("http.%s mismatch: got=%q want=%q", key, got, want)
+		}
+	}
+}
+
+func TestLoggingHTTPHandlerTrustedProxiesDisabled(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	h := slogtool.LoggingHTTPHandler(
+		slog.New(slog.NewJSONHandler(buf, nil)),
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("ok"))
+		}),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/test", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	obj := readSingleLogObject(t, buf)
+	httpObj, _ := obj["http"].(map[string]any)
+
+	for _, key := range []string{"client_ip", "peer_ip"} {
+		if _, ok := httpObj[key]; ok {
+			t.Errorf("unexpected http.%s field", key)
+		}
+	}
+}
+
+func TestLoggingHTTPHandlerTrustedProxiesLogFormat(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	h := slogtool.LoggingHTTPHandler(
+		slog.New(slog.DiscardHandler),
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("ok"))
+		}),
+		slogtool.LoggingOptionLogFormat(buf, slogtool.MustParseLogFormat("$remote_addr $status")),
+		slogtool.LoggingOptionTrustedProxies(mustParseTrustedProxies(t, "10.0.0.0/8")...),
+		slogtool.LoggingOptionClientIPHeader("X-Real-IP"),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/test", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Real-IP", "198.51.100.1")
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got := strings.TrimSpace(buf.String()); got != "198.51.100.1 200" {
+		t.Errorf("log line mismatch: got=%q want=%q", got, "198.51.100.1 200")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"log/slog"
+	"net/http"
 	"os"
 
 	"github.com/na4ma4/go-slogtool/prettylog"
@@ -13,6 +14,7 @@ type contextKey string
 
 const (
 	contextKeyLogMgr contextKey = "logmanager"
+	contextKeyLogger contextKey = "logger"
 )
 
 // NewSlogManagerInContext creates a new slog manager and a core logger, and stores the slog manager in the provided context.
@@ -61,3 +63,24 @@ func LogManagerFromContext(ctx context.Context) LogManager {
 	)
 	return logmgr
 }
+
+// ContextWithLogger returns a copy of ctx with the logger stored in it.
+func ContextWithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKeyLogger, logger)
+}
+
+// LoggerFromContext retrieves the logger stored by [ContextWithLogger] or the HTTP logging handler from
+// the provided context, if it does not exist it returns [slog.Default].
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	if v, ok := ctx.Value(contextKeyLogger).(*slog.Logger); ok && v != nil {
+		return v
+	}
+
+	return slog.Default()
+}
+
+// LoggerFromRequest retrieves the request-scoped logger stored by the HTTP logging handler from the
+// request context, if it does not exist it returns [slog.Default].
+func LoggerFromRequest(req *http.Request) *slog.Logger {
+	return LoggerFromContext(req.Context())
+}
@@ -0,0 +1,131 @@
+package slogtool
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+)
+
+// FanoutSink defines a writer, handler and minimum level for one of the outputs of the loggers
+// created by the SlogManager when [WithFanout] is used.
+//
+// A record is only passed to the sink if it is enabled by both the logger level and Level,
+// a nil Level only uses the logger level.
+type FanoutSink struct {
+	Writer  io.Writer
+	Handler CustomNewHandler
+	Level   slog.Leveler
+}
+
+// MultiHandler is a [slog.Handler] that passes each record to every wrapped handler that is
+// enabled for the record level.
+type MultiHandler struct {
+	handlers []slog.Handler
+}
+
+// NewMultiHandler returns a new MultiHandler wrapping handlers.
+func NewMultiHandler(handlers ...slog.Handler) *MultiHandler {
+	return &MultiHandler{
+		handlers: append([]slog.Handler(nil), handlers...),
+	}
+}
+
+// Enabled reports whether any of the wrapped handlers handles records at the given level.
+func (h *MultiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range h.handlers {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Handle passes a clone of the record to each wrapped handler that is enabled for the record level,
+// errors returned by the wrapped handlers are joined.
+func (h *MultiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+
+	for _, handler := range h.handlers {
+		if !handler.Enabled(ctx, r.Level) {
+			continue
+		}
+
+		if err := handler.Handle(ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// WithAttrs returns a new MultiHandler wrapping the handlers returned by each wrapped handler's WithAttrs.
+func (h *MultiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, 0, len(h.handlers))
+	for _, handler := range h.handlers {
+		handlers = append(handlers, handler.WithAttrs(append([]slog.Attr(nil), attrs...)))
+	}
+
+	return &MultiHandler{handlers: handlers}
+}
+
+// WithGroup returns a new MultiHandler wrapping the handlers returned by each wrapped handler's WithGroup.
+func (h *MultiHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	handlers := make([]slog.Handler, 0, len(h.handlers))
+	for _, handler := range h.handlers {
+		handlers = append(handlers, handler.WithGroup(name))
+	}
+
+	return &MultiHandler{handlers: handlers}
+}
+
+// sinkLeveler is a [slog.Leveler] that returns the highest of the logger and sink levels.
+type sinkLeveler struct {
+	logger slog.Leveler
+	sink   slog.Leveler
+}
+
+// Level returns the highest of the logger and sink levels.
+func (l sinkLeveler) Level() slog.Level {
+	return max(l.logger.Level(), l.sink.Level())
+}
+
+// fanoutHandler returns a [MultiHandler] with a handler for each of the fan-out sinks,
+// or nil if [WithFanout] is not used.
+func (a *SlogManager) fanoutHandler(name string, opts *slog.HandlerOptions) slog.Handler {
+	a.lock.RLock()
+	sinks, defaultWriter, defaultHandler := a.sinks, a.defaultWriter, a.coreNewHandler
+	a.lock.RUnlock()
+
+	if len(sinks) == 0 {
+		return nil
+	}
+
+	handlers := make([]slog.Handler, 0, len(sinks))
+
+	for _, sink := range sinks {
+		sinkOpts := *opts
+		if sink.Level != nil {
+			sinkOpts.Level = sinkLeveler{logger: opts.Level, sink: sink.Level}
+		}
+
+		w := sink.Writer
+		if w == nil {
+			w = defaultWriter
+		}
+
+		newHandler := sink.Handler
+		if newHandler == nil {
+			newHandler = defaultHandler
+		}
+
+		handlers = append(handlers, a.withLoggerName(name, newHandler(name, w, &sinkOpts)))
+	}
+
+	return &MultiHandler{handlers: handlers}
+}
@@ -0,0 +1,132 @@
+package slogtool_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/na4ma4/go-slogtool"
+)
+
+var errTestHandler = errors.New("test handler error")
+
+// errorHandler is a handler that always returns an error.
+type errorHandler struct {
+	slog.Handler
+}
+
+func (h errorHandler) Handle(context.Context, slog.Record) error {
+	return errTestHandler
+}
+
+func TestMultiHandler(t *testing.T) {
+	t.Parallel()
+
+	textBuf := bytes.NewBuffer(nil)
+	jsonBuf := bytes.NewBuffer(nil)
+	ctx := context.Background()
+	logger := slog.New(slogtool.NewMultiHandler(
+		slog.NewTextHandler(textBuf, &slog.HandlerOptions{Level: slog.LevelDebug}),
+		slog.NewJSONHandler(jsonBuf, &slog.HandlerOptions{Level: slog.LevelWarn}),
+	)).With(slog.String("attr", "value")).WithGroup("grp")
+
+	logger.DebugContext(ctx, "msg:debug", slog.Int("i", 1))
+	logger.WarnContext(ctx, "msg:warn", slog.Int("i", 2))
+
+	expectLogLines(t, textBuf, []string{
+		"time=" + timeTestString + " level=DEBUG msg=msg:debug attr=value grp.i=1",
+		"time=" + timeTestString + " level=WARN msg=msg:warn attr=value grp.i=2",
+	})
+	expectLogLines(t, jsonBuf, []string{
+		`{"time":"` + timeTestString + `","level":"WARN","msg":"msg:warn","attr":"value","grp":{"i":2}}`,
+	})
+}
+
+func TestMultiHandlerEnabled(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	h := slogtool.NewMultiHandler(
+		slog.NewTextHandler(bytes.NewBuffer(nil), &slog.HandlerOptions{Level: slog.LevelWarn}),
+		slog.NewTextHandler(bytes.NewBuffer(nil), &slog.HandlerOptions{Level: slog.LevelError}),
+	)
+
+	if h.Enabled(ctx, slog.LevelInfo) {
+		t.Error("expected MultiHandler to be disabled for INFO")
+	}
+	if !h.Enabled(ctx, slog.LevelWarn) {
+		t.Error("expected MultiHandler to be enabled for WARN")
+	}
+}
+
+func TestMultiHandlerErrors(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	ctx := context.Background()
+	h := slogtool.NewMultiHandler(
+		errorHandler{slog.NewTextHandler(bytes.NewBuffer(nil), nil)},
+		slog.NewTextHandler(buf, nil),
+		errorHandler{slog.NewTextHandler(bytes.NewBuffer(nil), nil)},
+	)
+
+	err := h.Handle(ctx, slog.NewRecord(time.Now(), slog.LevelInfo, "msg:info", 0))
+	if !errors.Is(err, errTestHandler) {
+		t.Errorf("Handle: got error '%v', want '%v'", err, errTestHandler)
+	}
+
+	if errs, ok := err.(interface{ Unwrap() []error }); !ok || len(errs.Unwrap()) != 2 {
+		t.Errorf("Handle: expected 2 joined errors, got '%v'", err)
+	}
+
+	expectLogLines(t, buf, []string{
+		"time=" + timeTestString + " level=INFO msg=msg:info",
+	})
+}
+
+func TestSlogManagerWithFanout(t *testing.T) {
+	t.Parallel()
+
+	consoleBuf := bytes.NewBuffer(nil)
+	fileBuf := bytes.NewBuffer(nil)
+	routeBuf := bytes.NewBuffer(nil)
+	ctx := context.Background()
+	testLog, err := slogtool.NewSlogManager(
+		ctx,
+		slogtool.WithDefaultLevel(slog.LevelInfo),
+		slogtool.WithLoggerName(""),
+		slogtool.WithFanout(
+			slogtool.FanoutSink{Writer: consoleBuf},
+			slogtool.FanoutSink{Writer: fileBuf, Handler: slogtool.JSONHandler, Level: slog.LevelWarn},
+		),
+		slogtool.WithRoute("Audit", routeBuf, nil),
+	)
+	if err != nil {
+		t.Fatalf("NewSlogManager returned error: %v", err)
+	}
+
+	server := testLog.Named("Server")
+	audit := testLog.Named("Audit")
+
+	server.DebugContext(ctx, "server:debug")
+	server.InfoContext(ctx, "server:info")
+	server.WarnContext(ctx, "server:warn")
+	audit.WarnContext(ctx, "audit:warn")
+
+	testLog.SetLevel("Server", slog.LevelError)
+	server.WarnContext(ctx, "server:warn2")
+
+	expectLogLines(t, consoleBuf, []string{
+		"time=" + timeTestString + " level=INFO msg=server:info logger=Server",
+		"time=" + timeTestString + " level=WARN msg=server:warn logger=Server",
+	})
+	expectLogLines(t, fileBuf, []string{
+		`{"time":"` + timeTestString + `","level":"WARN","msg":"server:warn","logger":"Server"}`,
+	})
+	expectLogLines(t, routeBuf, []string{
+		"time=" + timeTestString + " level=WARN msg=audit:warn logger=Audit",
+	})
+}
@@ -1,13 +1,17 @@
 package slogtool
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net"
 	"net/http"
 	"net/url"
+	"slices"
+	"sync/atomic"
 	"time"
 )
 
@@ -25,30 +29,95 @@ type loggingHandler struct {
 // ServeHTTP wraps the next handler ServeHTTP.
 func (h loggingHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	t := time.Now()
-	logger := makeLogger(w)
+	logger := makeLogger(w, t)
 	url := *req.URL
-	h.handler.ServeHTTP(logger, req)
-	writeLog(context.Background(), &h, req, url, t, logger.Status(), logger.Size())
+
+	ctx := req.Context()
+	id := h.requestID(req)
+
+	if id != "" {
+		ctx = ContextWithRequestID(ctx, id)
+
+		if h.opts.requestID {
+			w.Header().Set(h.requestIDHeader(), id)
+		}
+	}
+
+	if h.opts.traceContext {
+		if tc, ok := TraceContextFromRequest(req); ok {
+			ctx = ContextWithTraceContext(ctx, tc)
+		}
+	}
+
+	req = req.WithContext(ContextWithLogger(ctx, h.requestLogger(req, id)))
+	bodies := capturedBodies{
+		request:  h.opts.captureRequestBody(req),
+		response: logger.captureBody(h.opts.responseBodyLimit),
+	}
+	readTiming := h.opts.timeRequestBody(req)
+
+	recovered := h.serveNext(logger.wrap(), req)
+	status := logger.Status()
+
+	if recovered != nil {
+		if !recovered.isAbort() {
+			writePanicLog(req.Context(), &h, req, recovered)
+		}
+
+		if !logger.wroteHeader() && !logger.hijacked {
+			logger.WriteHeader(http.StatusInternalServerError)
+		}
+
+		status = http.StatusInternalServerError
+	}
+
+	if h.opts.metrics != nil {
+		h.opts.metrics.observe(req, status, logger.Size(), time.Since(t))
+	}
+
+	writeLog(req.Context(), &h, req, url, logger.timings(readTiming), status, logger.Size(), logger.Header(), bodies)
+
+	if recovered != nil && recovered.isAbort() && h.opts.repanicAbort {
+		panic(recovered.value)
+	}
 }
 
-type loggingResponseWriter interface {
-	http.ResponseWriter
-	http.Flusher
-	http.Pusher
-	Status() int
-	Size() int
+// requestLogger returns the request-scoped logger stored in the request context for the next handler.
+func (h loggingHandler) requestLogger(req *http.Request, id string) *slog.Logger {
+	attrs := make([]any, 0, 3) //nolint:mnd // request id, method and path.
+
+	if id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+
+	attrs = append(attrs,
+		slog.String("method", req.Method),
+		slog.String("path", h.opts.logURI(req.URL.Path)),
+	)
+
+	return h.logger.With(attrs...)
 }
 
-func makeLogger(w http.ResponseWriter) loggingResponseWriter {
-	return &responseLogger{w: w, status: http.StatusOK, size: 0}
+func makeLogger(w http.ResponseWriter, start time.Time) *responseLogger {
+	return &responseLogger{w: w, status: http.StatusOK, size: 0, start: start}
 }
 
 // responseLogger is wrapper of [http.ResponseWriter] that keeps track of its HTTP
 // status code and body size.
+//
+// The wrapper passed to the next handler is returned by wrap, it only implements the optional
+// interfaces that the underlying ResponseWriter implements.
 type responseLogger struct {
-	w      http.ResponseWriter
-	status int
-	size   int
+	w            http.ResponseWriter
+	status       int
+	size         int
+	body         *bodyCapture
+	header       bool
+	hijacked     bool
+	hijackedSize atomic.Int64
+	start        time.Time
+	firstByte    time.Time
+	lastWrite    time.Time
 }
 
 // Push implements the [http.Pusher] interface, if the underlying ResponseWriter does not implement [http.Pusher],
@@ -64,6 +133,64 @@ func (l *responseLogger) Push(target string, opts *http.PushOptions) error {
 	return p.Push(target, opts)
 }
 
+// Hijack implements the [http.Hijacker] interface, if the underlying ResponseWriter does not implement
+// [http.Hijacker], it returns ErrUnimplemented.
+//
+// A hijacked connection is logged with status `101 Switching Protocols` and the bytes written to it
+// before the request is logged.
+func (l *responseLogger) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := l.w.(http.Hijacker)
+	if !ok {
+		return nil, nil, ErrUnimplemented
+	}
+
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to hijack: %w", err)
+	}
+
+	l.hijacked = true
+	l.header = true
+	l.markFirstByte()
+	l.status = http.StatusSwitchingProtocols
+
+	// the returned writer is wrapped (keeping its buffer size) instead of replaced, it can already
+	// contain buffered data.
+	rw = bufio.NewReadWriter(rw.Reader, bufio.NewWriterSize(
+		&countingWriter{w: rw.Writer, size: &l.hijackedSize}, rw.Writer.Size(),
+	))
+	conn = &countingConn{Conn: conn, size: &l.hijackedSize}
+
+	return conn, rw, nil
+}
+
+// ReadFrom implements the [io.ReaderFrom] interface, it uses the underlying ResponseWriter's ReadFrom
+// (e.g. sendfile) unless the response body is being captured.
+func (l *responseLogger) ReadFrom(src io.Reader) (int64, error) {
+	rf, ok := l.w.(io.ReaderFrom)
+	if !ok || l.body != nil {
+		//nolint:wrapcheck // Write already wraps errors.
+		return io.Copy(struct{ io.Writer }{l}, src)
+	}
+
+	l.markFirstByte()
+	n, err := rf.ReadFrom(src)
+	l.header = true
+	l.size += int(n)
+	l.markLastWrite()
+
+	if err != nil {
+		return n, fmt.Errorf("unable to read from: %w", err)
+	}
+
+	return n, nil
+}
+
+// Unwrap returns the underlying ResponseWriter, for use by [http.ResponseController].
+func (l *responseLogger) Unwrap() http.ResponseWriter {
+	return l.w
+}
+
 // Header implements the [http.ResponseWriter] interface.
 func (l *responseLogger) Header() http.Header {
 	return l.w.Header()
@@ -71,8 +198,12 @@ func (l *responseLogger) Header() http.Header {
 
 // Write implements the [http.ResponseWriter] interface, it writes to the underlying ResponseWriter and keeps track of the size of the response body.
 func (l *responseLogger) Write(b []byte) (int, error) {
+	l.markFirstByte()
 	size, err := l.w.Write(b)
+	l.header = true
 	l.size += size
+	l.markLastWrite()
+	l.body.write(b[:size])
 
 	if err != nil {
 		return size, fmt.Errorf("unable to write: %w", err)
@@ -83,8 +214,41 @@ func (l *responseLogger) Write(b []byte) (int, error) {
 
 // WriteHeader implements the [http.ResponseWriter] interface, it writes the header to the underlying ResponseWriter and keeps track of the status code.
 func (l *responseLogger) WriteHeader(s int) {
+	l.markFirstByte()
 	l.w.WriteHeader(s)
 	l.status = s
+
+	if s >= http.StatusOK {
+		l.header = true
+	}
+}
+
+// markFirstByte records the time of the first byte, it is called before the response is handed to
+// the underlying ResponseWriter.
+func (l *responseLogger) markFirstByte() {
+	if l.firstByte.IsZero() {
+		l.firstByte = time.Now()
+	}
+}
+
+// markLastWrite records the time of the last write, it is called after the response body is written.
+func (l *responseLogger) markLastWrite() {
+	l.lastWrite = time.Now()
+}
+
+// timings returns the phase timings of the response.
+func (l *responseLogger) timings(read *timingReadCloser) requestTimings {
+	return requestTimings{
+		start:     l.start,
+		firstByte: l.firstByte,
+		lastWrite: l.lastWrite,
+		read:      read,
+	}
+}
+
+// wroteHeader returns true if the header has been written, either by WriteHeader or by Write.
+func (l *responseLogger) wroteHeader() bool {
+	return l.header
 }
 
 // Status returns the HTTP status code of the response.
@@ -92,15 +256,23 @@ func (l *responseLogger) Status() int {
 	return l.status
 }
 
-// Size returns the size of the response body.
+// Size returns the size of the response body, including the bytes written to a hijacked connection.
 func (l *responseLogger) Size() int {
-	return l.size
+	return l.size + int(l.hijackedSize.Load())
+}
+
+// captureBody starts keeping a copy of up to limit bytes of the response body, it returns nil if limit is zero.
+func (l *responseLogger) captureBody(limit int) *bodyCapture {
+	l.body = newBodyCapture(limit)
+
+	return l.body
 }
 
 // Flush implements the [http.Flusher] interface, it flushes the underlying ResponseWriter if it implements [http.Flusher].
 func (l *responseLogger) Flush() {
 	f, ok := l.w.(http.Flusher)
 	if ok {
+		l.header = true
 		f.Flush()
 	}
 }
@@ -114,27 +286,51 @@ func slogFieldOrSkip(returnField bool, field slog.Attr) slog.Attr {
 	return slog.Attr{}
 }
 
-// writeLog writes a log entry for req to w in Apache Combined Log Format.
-// ts is the timestamp with which the entry should be logged.
-// status and size are used to provide the response HTTP status and size.
-func writeLog(ctx context.Context, lh *loggingHandler, req *http.Request, url url.URL, ts time.Time, status, size int) {
-	if lh.opts.ignoreRequestCallback != nil && lh.opts.ignoreRequestCallback(req) {
+// writeLog writes a log entry for req to the logger, or to the writer in the log format if
+// [LoggingOptionLogFormat] is used.
+// timings are the phase timings of the request, the start is the timestamp with which the entry should be logged.
+// status, size and respHeader are used to provide the response HTTP status, size and headers.
+// bodies are the captured request and response bodies, logged in a separate record.
+func writeLog(
+	ctx context.Context,
+	lh *loggingHandler,
+	req *http.Request,
+	url url.URL,
+	timings requestTimings,
+	status, size int,
+	respHeader http.Header,
+	bodies capturedBodies,
+) {
+	if lh.opts.ignored(req) {
 		return
 	}
 
 	// Extract `X-Logging-Username` from request, added by authentication function earlier in process.
+	rawUsername := ""
 	username := "-"
 	if lh.opts.extractUsernameCallback != nil {
 		if u, ok := lh.opts.extractUsernameCallback(req); ok {
+			rawUsername = u
 			username = sanitizeUsername(u)
 		}
 	}
 
+	requestID, _ := RequestIDFromContext(ctx)
+	trace, hasTrace := TraceContextFromContext(ctx)
+	hasTrace = hasTrace && lh.opts.traceContext
+
 	host, _, err := net.SplitHostPort(req.RemoteAddr)
 	if err != nil {
 		host = req.RemoteAddr
 	}
 
+	hasClientIP := len(lh.opts.trustedProxies) > 0
+	clientIP, peerIP := "", ""
+	if hasClientIP {
+		client, peer := ResolveClientIP(req, lh.opts.trustedProxies, lh.opts.clientIPHeader)
+		clientIP, peerIP = addrString(client), addrString(peer)
+	}
+
 	uri := req.RequestURI
 	if req.ProtoMajor == 2 && req.Method == http.MethodConnect {
 		uri = req.Host
@@ -144,6 +340,37 @@ func writeLog(ctx context.Context, lh *loggingHandler, req *http.Request, url ur
 		uri = url.RequestURI()
 	}
 
+	ts := timings.start
+	now := time.Now()
+	duration := now.Sub(ts)
+
+	if lh.opts.logFormat != nil {
+		remoteAddr := host
+		if hasClientIP {
+			remoteAddr = clientIP
+		}
+
+		_ = lh.opts.logFormat.write(lh.opts.logFormatWriter, &accessLogEntry{
+			opts:      lh.opts,
+			req:       req,
+			host:      remoteAddr,
+			username:  rawUsername,
+			uri:       lh.opts.redactURI(uri),
+			referer:   lh.opts.redactURI(req.Referer()),
+			ts:        ts,
+			duration:  duration,
+			status:    status,
+			size:      size,
+			requestID: requestID,
+		})
+
+		writeBodyLog(ctx, lh, req, uri, status, respHeader, bodies)
+
+		return
+	}
+
+	level, slow := lh.opts.requestLevel(req, status, duration)
+
 	fields := []slog.Attr{
 		slog.Group("http", // 0
 			slog.String("host", host),         // 1
@@ -152,27 +379,94 @@ func writeLog(ctx context.Context, lh *loggingHandler, req *http.Request, url ur
 				slog.String("timestamp", ts.Format(time.RFC3339Nano)),
 			), // 3
 			slog.String("method", req.Method),                             // 4
-			slog.String("uri", sanitizeURI(uri)),                          // 5
+			slog.String("uri", lh.opts.logURI(uri)),                       // 5
 			slog.String("proto", req.Proto),                               // 6
 			slog.Int("status", status),                                    // 7
 			slog.Int("size", size),                                        // 8
-			slog.String("referer", sanitizeURI(req.Referer())),            // 9
+			slog.String("referer", lh.opts.logURI(req.Referer())),         // 9
 			slog.String("user-agent", sanitizeUserAgent(req.UserAgent())), // 10
 			slogFieldOrSkip(lh.opts.includeTiming,
-				slog.Duration("request-time", time.Since(ts)),
+				slog.Duration("request-time", duration),
 			), // 11
 			slogFieldOrSkip(lh.opts.includeXForwardedFor,
 				slog.String("forwarded_for", req.Header.Get("X-Forwarded-For")),
 			), // 12
+			slogFieldOrSkip(lh.opts.requestID,
+				slog.String("request_id", requestID),
+			), // 13
+			slogFieldOrSkip(hasTrace,
+				slog.String("trace_id", trace.TraceID),
+			), // 14
+			slogFieldOrSkip(hasTrace,
+				slog.String("span_id", trace.SpanID),
+			), // 15
+			slogFieldOrSkip(hasTrace,
+				slog.String("trace_flags", trace.TraceFlags),
+			), // 16
+			lh.opts.headerGroup("request_headers", req.Header, lh.opts.requestHeaders),   // 17
+			lh.opts.headerGroup("response_headers", respHeader, lh.opts.responseHeaders), // 18
+			slogFieldOrSkip(slow, slog.Bool("slow", true)),                               // 19
+			slogFieldOrSkip(lh.opts.includeTiming,
+				slog.Group("", timings.attrs(now)...),
+			), // 20
+			slogFieldOrSkip(hasClientIP,
+				slog.String("client_ip", clientIP),
+			), // 21
+			slogFieldOrSkip(hasClientIP,
+				slog.String("peer_ip", peerIP),
+			), // 22
+			slogFieldOrSkip(req.Pattern != "",
+				slog.String("route", req.Pattern),
+			), // 23
 		),
 	}
 
 	lh.logger.LogAttrs(
 		ctx,
-		lh.opts.logLevel.Level(),
+		level,
 		"Request",
 		fields...,
 	)
+
+	writeBodyLog(ctx, lh, req, uri, status, respHeader, bodies)
+}
+
+// ignored returns true if the request should be ignored in logging, the route is the [http.ServeMux] pattern
+// that matched the request, it is only available after the request has been dispatched.
+func (o *loggingOptions) ignored(req *http.Request) bool {
+	if req.Pattern != "" && slices.Contains(o.ignoreRoutes, req.Pattern) {
+		return true
+	}
+
+	return o.ignoreRequestCallback != nil && o.ignoreRequestCallback(req)
+}
+
+// DefaultStatusLevel is a LoggingLevelCallback that returns Info for 1xx, 2xx and 3xx responses, Warn for 4xx
+// responses and Error for 5xx responses.
+func DefaultStatusLevel(_ *http.Request, status int, _ time.Duration) slog.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return slog.LevelError
+	case status >= http.StatusBadRequest:
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// requestLevel returns the level a request is logged at and if the request is slow.
+func (o *loggingOptions) requestLevel(req *http.Request, status int, duration time.Duration) (slog.Level, bool) {
+	level := o.logLevel.Level()
+	if o.levelCallback != nil {
+		level = o.levelCallback(req, status, duration)
+	}
+
+	slow := o.slowThreshold > 0 && duration > o.slowThreshold
+	if slow {
+		level = max(level, o.slowLevel.Level())
+	}
+
+	return level, slow
 }
 
 // LoggingHTTPHandler return a [http.Handler] that wraps h and logs requests to out using
@@ -187,6 +481,9 @@ func LoggingHTTPHandler(logger *slog.Logger, httpHandler http.Handler, opts ...l
 		includeTimestamp:     true,
 		includeXForwardedFor: false,
 		logLevel:             slog.LevelInfo,
+		redactHeaders:        defaultRedactHeaders(),
+		redactQueryParams:    defaultRedactQueryParams(),
+		bodyLogLevel:         slog.LevelDebug,
 	}
 
 	for _, f := range opts {
@@ -211,6 +508,9 @@ func LoggingHTTPHandlerWrapper(logger *slog.Logger, opts ...loggingOptionsFunc)
 		includeTimestamp:     true,
 		includeXForwardedFor: false,
 		logLevel:             slog.LevelInfo,
+		redactHeaders:        defaultRedactHeaders(),
+		redactQueryParams:    defaultRedactQueryParams(),
+		bodyLogLevel:         slog.LevelDebug,
 	}
 
 	for _, f := range opts {
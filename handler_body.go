@@ -0,0 +1,217 @@
+package slogtool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// bodyCapture keeps a copy of up to limit bytes of a request or response body.
+type bodyCapture struct {
+	limit     int
+	buf       bytes.Buffer
+	truncated bool
+}
+
+func newBodyCapture(limit int) *bodyCapture {
+	if limit <= 0 {
+		return nil
+	}
+
+	return &bodyCapture{limit: limit}
+}
+
+// write copies p to the capture buffer, up to the limit.
+func (c *bodyCapture) write(p []byte) {
+	if c == nil || len(p) == 0 {
+		return
+	}
+
+	if room := c.limit - c.buf.Len(); len(p) > room {
+		c.truncated = true
+		p = p[:room]
+	}
+
+	c.buf.Write(p)
+}
+
+// captureReadCloser is an [io.ReadCloser] that copies what is read to a bodyCapture.
+type captureReadCloser struct {
+	io.ReadCloser
+
+	capture *bodyCapture
+}
+
+// Read implements the [io.Reader] interface, it reads from the underlying body and keeps a copy of what is read.
+func (r *captureReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.capture.write(p[:n])
+
+	return n, err //nolint:wrapcheck // io.EOF must not be wrapped.
+}
+
+// capturedBodies are the request and response bodies captured for a request.
+type capturedBodies struct {
+	request  *bodyCapture
+	response *bodyCapture
+}
+
+// captureRequestBody replaces the body of req with one that copies what the next handler reads,
+// if request body capture is enabled and the content type is allowed.
+func (o *loggingOptions) captureRequestBody(req *http.Request) *bodyCapture {
+	if req.Body == nil || req.Body == http.NoBody || !o.bodyContentTypeAllowed(req.Header.Get("Content-Type")) {
+		return nil
+	}
+
+	capture := newBodyCapture(o.requestBodyLimit)
+	if capture != nil {
+		req.Body = &captureReadCloser{ReadCloser: req.Body, capture: capture}
+	}
+
+	return capture
+}
+
+// bodyContentTypeAllowed returns true if no content types are configured or the media type of contentType
+// has one of the configured content types as a prefix, e.g. `text/` matches `text/plain`.
+func (o *loggingOptions) bodyContentTypeAllowed(contentType string) bool {
+	if len(o.bodyContentTypes) == 0 {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, allowed := range o.bodyContentTypes {
+		if strings.HasPrefix(mediaType, strings.ToLower(allowed)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bodyStatusAllowed returns true if no status range is configured or status is in the range.
+func (o *loggingOptions) bodyStatusAllowed(status int) bool {
+	if o.bodyStatusMin == 0 && o.bodyStatusMax == 0 {
+		return true
+	}
+
+	return status >= o.bodyStatusMin && status <= o.bodyStatusMax
+}
+
+// bodyValue returns the captured body with the configured JSON fields redacted.
+//
+// If fields are configured to be redacted a JSON body that can not be parsed (e.g. because it was
+// truncated) is replaced by [RedactedValue].
+func (o *loggingOptions) bodyValue(contentType string, c *bodyCapture) string {
+	if len(o.bodyRedactFields) == 0 {
+		return c.buf.String()
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+		return c.buf.String()
+	}
+
+	var v any
+	if c.truncated || json.Unmarshal(c.buf.Bytes(), &v) != nil {
+		return RedactedValue
+	}
+
+	out, err := json.Marshal(redactJSONFields(v, o.bodyRedactFields))
+	if err != nil {
+		return RedactedValue
+	}
+
+	return string(out)
+}
+
+// redactJSONFields replaces the value of the named fields (matched case-insensitively) at any depth.
+func redactJSONFields(v any, fields []string) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, item := range t {
+			redact := false
+			for _, field := range fields {
+				if strings.EqualFold(k, field) {
+					redact = true
+					break
+				}
+			}
+
+			if redact {
+				t[k] = RedactedValue
+			} else {
+				t[k] = redactJSONFields(item, fields)
+			}
+		}
+	case []any:
+		for i, item := range t {
+			t[i] = redactJSONFields(item, fields)
+		}
+	}
+
+	return v
+}
+
+// bodyAttrs returns the attributes for a captured body, or nil if nothing was captured.
+func (o *loggingOptions) bodyAttrs(key, contentType string, c *bodyCapture) []any {
+	if c == nil {
+		return nil
+	}
+
+	return []any{
+		slog.String(key, o.bodyValue(contentType, c)),
+		slogFieldOrSkip(c.truncated, slog.Bool(key+"_truncated", true)),
+	}
+}
+
+// writeBodyLog logs the captured request and response bodies in a separate record at the body log level.
+func writeBodyLog(
+	ctx context.Context,
+	lh *loggingHandler,
+	req *http.Request,
+	uri string,
+	status int,
+	respHeader http.Header,
+	bodies capturedBodies,
+) {
+	if !lh.opts.bodyStatusAllowed(status) {
+		return
+	}
+
+	if bodies.response != nil && !lh.opts.bodyContentTypeAllowed(respHeader.Get("Content-Type")) {
+		bodies.response = nil
+	}
+
+	if bodies.request == nil && bodies.response == nil {
+		return
+	}
+
+	attrs := []any{
+		slog.String("method", req.Method),
+		slog.String("uri", lh.opts.logURI(uri)),
+		slog.Int("status", status),
+	}
+
+	if id, ok := RequestIDFromContext(ctx); ok {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+
+	attrs = append(attrs, lh.opts.bodyAttrs("request_body", req.Header.Get("Content-Type"), bodies.request)...)
+	attrs = append(attrs, lh.opts.bodyAttrs("response_body", respHeader.Get("Content-Type"), bodies.response)...)
+
+	lh.logger.LogAttrs(
+		ctx,
+		lh.opts.bodyLogLevel.Level(),
+		"Request Body",
+		slog.Group("http", attrs...),
+	)
+}
@@ -0,0 +1,209 @@
+package slogtool_test
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/na4ma4/go-slogtool"
+)
+
+func echoBodyHandler(status int, contentType string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(status)
+		_, _ = w.Write(body)
+	})
+}
+
+func TestLoggingHTTPHandlerBodies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		handler func(base *slog.Logger) http.Handler
+		body    string
+		expect  map[string]any
+	}{
+		{
+			"json-redacted",
+			func(base *slog.Logger) http.Handler {
+				return slogtool.LoggingHTTPHandler(base, echoBodyHandler(http.StatusOK, "application/json"),
+					slogtool.LoggingOptionRequestBody(1024),
+					slogtool.LoggingOptionResponseBody(1024),
+					slogtool.LoggingOptionBodyRedactFields("password", "Token"),
+				)
+			},
+			`{"user":"bob","password":"secret","nested":[{"token":"abc"}]}`,
+			map[string]any{
+				"method":        "POST",
+				"uri":           "http://example.com/hook",
+				"status":        float64(200),
+				"request_body":  `{"nested":[{"token":"[REDACTED]"}],"password":"[REDACTED]","user":"bob"}`,
+				"response_body": `{"nested":[{"token":"[REDACTED]"}],"password":"[REDACTED]","user":"bob"}`,
+			},
+		},
+		{
+			"truncated",
+			func(base *slog.Logger) http.Handler {
+				return slogtool.LoggingHTTPHandler(base, echoBodyHandler(http.StatusOK, "text/plain"),
+					slogtool.LoggingOptionRequestBody(5),
+				)
+			},
+			"0123456789",
+			map[string]any{
+				"method":                 "POST",
+				"uri":                    "http://example.com/hook",
+				"status":                 float64(200),
+				"request_body":           "01234",
+				"request_body_truncated": true,
+			},
+		},
+		{
+			"truncated-json-redacted",
+			func(base *slog.Logger) http.Handler {
+				return slogtool.LoggingHTTPHandler(base, echoBodyHandler(http.StatusOK, "application/json"),
+					slogtool.LoggingOptionResponseBody(5),
+					slogtool.LoggingOptionBodyRedactFields("password"),
+				)
+			},
+			`{"password":"secret"}`,
+			map[string]any{
+				"method":                  "POST",
+				"uri":                     "http://example.com/hook",
+				"status":                  float64(200),
+				"response_body":           slogtool.RedactedValue,
+				"response_body_truncated": true,
+			},
+		},
+		{
+			"status-range-excluded",
+			func(base *slog.Logger) http.Handler {
+				return slogtool.LoggingHTTPHandler(base, echoBodyHandler(http.StatusOK, "text/plain"),
+					slogtool.LoggingOptionRequestBody(1024),
+					slogtool.LoggingOptionBodyStatus(500, 599),
+				)
+			},
+			"body",
+			nil,
+		},
+		{
+			"status-range-included",
+			func(base *slog.Logger) http.Handler {
+				return slogtool.LoggingHTTPHandler(base, echoBodyHandler(http.StatusBadGateway, "text/plain"),
+					slogtool.LoggingOptionResponseBody(1024),
+					slogtool.LoggingOptionBodyStatus(500, 599),
+				)
+			},
+			"body",
+			map[string]any{
+				"method":        "POST",
+				"uri":           "http://example.com/hook",
+				"status":        float64(502),
+				"response_body": "body",
+			},
+		},
+		{
+			"content-type-excluded",
+			func(base *slog.Logger) http.Handler {
+				return slogtool.LoggingHTTPHandler(base, echoBodyHandler(http.StatusOK, "application/octet-stream"),
+					slogtool.LoggingOptionRequestBody(1024),
+					slogtool.LoggingOptionResponseBody(1024),
+					slogtool.LoggingOptionBodyContentTypes("text/"),
+				)
+			},
+			"body",
+			map[string]any{
+				"method":       "POST",
+				"uri":          "http://example.com/hook",
+				"status":       float64(200),
+				"request_body": "body",
+			},
+		},
+		{
+			"response-content-type-filtered",
+			func(base *slog.Logger) http.Handler {
+				return slogtool.LoggingHTTPHandler(base, echoBodyHandler(http.StatusOK, "image/png"),
+					slogtool.LoggingOptionResponseBody(100),
+					slogtool.LoggingOptionBodyContentTypes("application/json"),
+				)
+			},
+			"body",
+			nil,
+		},
+		{
+			"disabled",
+			func(base *slog.Logger) http.Handler {
+				return slogtool.LoggingHTTPHandler(base, echoBodyHandler(http.StatusOK, "text/plain"))
+			},
+			"body",
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := bytes.NewBuffer(nil)
+			h := tt.handler(slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+			req := httptest.NewRequest(http.MethodPost, "http://example.com/hook", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+			if strings.HasPrefix(tt.body, "{") {
+				req.Header.Set("Content-Type", "application/json")
+			}
+
+			rw := httptest.NewRecorder()
+			h.ServeHTTP(rw, req)
+
+			if got := rw.Body.String(); got != tt.body {
+				t.Errorf("response body mismatch: got=%q want=%q", got, tt.body)
+			}
+
+			var bodyObj map[string]any
+
+			for _, obj := range readLogObjects(t, buf) {
+				if obj["msg"] != "Request Body" {
+					continue
+				}
+
+				if obj["level"] != "DEBUG" {
+					t.Errorf("level mismatch: got=%v want=DEBUG", obj["level"])
+				}
+
+				bodyObj, _ = obj["http"].(map[string]any)
+			}
+
+			if diff := cmp.Diff(bodyObj, tt.expect); diff != "" {
+				t.Errorf("body log: -got +want:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoggingHTTPHandlerBodiesLevel(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	h := slogtool.LoggingHTTPHandler(
+		slog.New(slog.NewJSONHandler(buf, nil)),
+		echoBodyHandler(http.StatusOK, "text/plain"),
+		slogtool.LoggingOptionRequestBody(1024),
+	)
+
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/hook", strings.NewReader("body"))
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	obj := readSingleLogObject(t, buf)
+	if obj["msg"] != "Request" {
+		t.Errorf("expected only the access log at INFO, got=%v", obj)
+	}
+}
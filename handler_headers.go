@@ -0,0 +1,81 @@
+package slogtool
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// RedactedValue is logged instead of the value of a redacted header, body field or URI part.
+const RedactedValue = "[REDACTED]"
+
+// defaultRedactHeaders returns the headers redacted by default.
+func defaultRedactHeaders() []string {
+	return []string{"Authorization", "Cookie", "Set-Cookie"}
+}
+
+// headerGroup returns a group of the allowed headers that are present in header, or an empty
+// [slog.Attr] if none are present.
+func (o *loggingOptions) headerGroup(key string, header http.Header, allowed []string) slog.Attr {
+	if len(allowed) == 0 || header == nil {
+		return slog.Attr{}
+	}
+
+	attrs := make([]any, 0, len(allowed))
+
+	for _, name := range allowed {
+		values := header.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+
+		attrs = append(attrs, slog.String(strings.ToLower(name), o.headerValue(name, values)))
+	}
+
+	if len(attrs) == 0 {
+		return slog.Attr{}
+	}
+
+	return slog.Group(key, attrs...)
+}
+
+// isRedactedHeader returns true if the value of the header is logged as [RedactedValue].
+func (o *loggingOptions) isRedactedHeader(name string) bool {
+	for _, redact := range o.redactHeaders {
+		if strings.EqualFold(name, redact) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// headerValue returns the redacted or sanitized and truncated value of a header.
+func (o *loggingOptions) headerValue(name string, values []string) string {
+	if o.isRedactedHeader(name) {
+		return RedactedValue
+	}
+
+	if strings.EqualFold(name, "Referer") {
+		redacted := make([]string, len(values))
+		for i, value := range values {
+			redacted[i] = o.redactURI(value)
+		}
+
+		values = redacted
+	}
+
+	v := sanitizeURI(strings.Join(values, ", "))
+
+	if o.headerMaxLength > 0 && len(v) > o.headerMaxLength {
+		v = v[:o.headerMaxLength]
+		for len(v) > 0 && !utf8.ValidString(v) {
+			v = v[:len(v)-1]
+		}
+
+		v += "..."
+	}
+
+	return v
+}
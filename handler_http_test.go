@@ -2,13 +2,19 @@ package slogtool_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 
 	"github.com/na4ma4/go-slogtool"
 )
@@ -29,6 +35,27 @@ func readSingleLogObject(t *testing.T, buf *bytes.Buffer) map[string]any {
 	return item
 }
 
+func readLogObjects(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	out := []map[string]any{}
+
+	for line := range strings.SplitSeq(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+
+		var item map[string]any
+		if err := json.Unmarshal([]byte(line), &item); err != nil {
+			t.Fatalf("expected JSON log line, got error: %v", err)
+		}
+
+		out = append(out, item)
+	}
+
+	return out
+}
+
 func TestLoggingHTTPHandlerRespectsUnderlyingHandlerLevel(t *testing.T) {
 	t.Parallel()
 
@@ -749,3 +776,452 @@ func TestLoggingHTTPHandlerWriteLogSpecialCharsSanitize(t *testing.T) {
 		t.Fatalf("referer should not contain newlines: %q", referer)
 	}
 }
+
+type contextValueKey struct{}
+
+// contextValueHandler is a handler that adds a context value to each record.
+type contextValueHandler struct {
+	slog.Handler
+}
+
+func (h contextValueHandler) Handle(ctx context.Context, r slog.Record) error {
+	if v, ok := ctx.Value(contextValueKey{}).(string); ok {
+		r.AddAttrs(slog.String("ctx", v))
+	}
+
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextValueHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextValueHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func TestLoggingHTTPHandlerRequestLogger(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	base := slog.New(contextValueHandler{slog.NewJSONHandler(buf, nil)})
+
+	h := slogtool.LoggingHTTPHandler(
+		base,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			slogtool.LoggerFromRequest(r).InfoContext(r.Context(), "inner")
+			_, _ = w.Write([]byte("ok"))
+		}),
+		slogtool.LoggingOptionTiming(false),
+		slogtool.LoggingOptionTimestamp(false),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/test?q=1", nil)
+	req.RemoteAddr = "127.0.0.1:1234"
+	req.Header.Set("X-Request-Id", "abc-123")
+	req = req.WithContext(context.WithValue(req.Context(), contextValueKey{}, "value"))
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected two log lines, got=%q", lines)
+	}
+
+	var inner, access map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &inner); err != nil {
+		t.Fatalf("expected JSON log line, got error: %v", err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &access); err != nil {
+		t.Fatalf("expected JSON log line, got error: %v", err)
+	}
+
+	for k, want := range map[string]string{
+		"msg":        "inner",
+		"request_id": "abc-123",
+		"method":     http.MethodGet,
+		"path":       "/test",
+		"ctx":        "value",
+	} {
+		if got, _ := inner[k].(string); got != want {
+			t.Errorf("inner log %s mismatch: got=%v want=%q", k, inner[k], want)
+		}
+	}
+
+	if got, _ := access["ctx"].(string); got != "value" {
+		t.Errorf("access log ctx mismatch: got=%v want=%q", access["ctx"], "value")
+	}
+	if _, ok := access["request_id"]; ok {
+		t.Errorf("access log should not contain request-scoped attributes, got=%v", access)
+	}
+}
+
+func TestLoggerFromContextDefault(t *testing.T) {
+	t.Parallel()
+
+	if got := slogtool.LoggerFromContext(context.Background()); got != slog.Default() {
+		t.Errorf("LoggerFromContext: expected slog.Default(), got=%v", got)
+	}
+
+	logger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	ctx := slogtool.ContextWithLogger(context.Background(), logger)
+
+	if got := slogtool.LoggerFromContext(ctx); got != logger {
+		t.Errorf("LoggerFromContext: expected stored logger, got=%v", got)
+	}
+}
+
+func TestLoggingHTTPHandlerHeaders(t *testing.T) {
+	t.Parallel()
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Header().Set("X-Not-Logged", "value")
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	tests := []struct {
+		name     string
+		handler  func(base *slog.Logger) http.Handler
+		request  map[string]any
+		response map[string]any
+	}{
+		{
+			"allowlist-default-redaction",
+			func(base *slog.Logger) http.Handler {
+				return slogtool.LoggingHTTPHandler(base, next,
+					slogtool.LoggingOptionRequestHeaders("Accept", "Authorization", "Cookie", "X-Missing"),
+					slogtool.LoggingOptionResponseHeaders("Content-Type", "Set-Cookie"),
+				)
+			},
+			map[string]any{
+				"accept":        "text/html, application/json",
+				"authorization": slogtool.RedactedValue,
+				"cookie":        slogtool.RedactedValue,
+			},
+			map[string]any{
+				"content-type": "text/plain",
+				"set-cookie":   slogtool.RedactedValue,
+			},
+		},
+		{
+			"custom-redaction-truncation",
+			func(base *slog.Logger) http.Handler {
+				return slogtool.LoggingHTTPHandlerWrapper(base,
+					slogtool.LoggingOptionRequestHeaders("Accept", "Authorization", "Cookie", "X-Missing"),
+					slogtool.LoggingOptionResponseHeaders("Content-Type", "Set-Cookie"),
+					slogtool.LoggingOptionRedactHeaders("Accept"),
+					slogtool.LoggingOptionHeaderMaxLength(6),
+				)(next)
+			},
+			map[string]any{
+				"accept":        slogtool.RedactedValue,
+				"authorization": "Bearer...",
+				"cookie":        "sessio...",
+			},
+			map[string]any{
+				"content-type": "text/p...",
+				"set-cookie":   "sessio...",
+			},
+		},
+		{
+			"disabled",
+			func(base *slog.Logger) http.Handler {
+				return slogtool.LoggingHTTPHandler(base, next)
+			},
+			nil,
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := bytes.NewBuffer(nil)
+			h := tt.handler(slog.New(slog.NewJSONHandler(buf, nil)))
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/test", nil)
+			req.RemoteAddr = "127.0.0.1:1234"
+			req.Header.Add("Accept", "text/html")
+			req.Header.Add("Accept", "application/json")
+			req.Header.Set("Authorization", "Bearer token")
+			req.Header.Set("Cookie", "session=secret")
+
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			obj := readSingleLogObject(t, buf)
+			httpObj, _ := obj["http"].(map[string]any)
+
+			reqHeaders, _ := httpObj["request_headers"].(map[string]any)
+			if diff := cmp.Diff(reqHeaders, tt.request, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("http.request_headers: -got +want:\n%s", diff)
+			}
+
+			respHeaders, _ := httpObj["response_headers"].(map[string]any)
+			if diff := cmp.Diff(respHeaders, tt.response, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("http.response_headers: -got +want:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoggingHTTPHandlerStatusLevels(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		status int
+		delay  time.Duration
+		expect string
+		slow   bool
+	}{
+		{"ok", http.StatusOK, 0, "INFO", false},
+		{"redirect", http.StatusFound, 0, "INFO", false},
+		{"not-found", http.StatusNotFound, 0, "WARN", false},
+		{"server-error", http.StatusInternalServerError, 0, "ERROR", false},
+		{"slow-ok", http.StatusOK, 20 * time.Millisecond, "WARN", true},
+		{"slow-server-error", http.StatusInternalServerError, 20 * time.Millisecond, "ERROR", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := bytes.NewBuffer(nil)
+			h := slogtool.LoggingHTTPHandler(
+				slog.New(slog.NewJSONHandler(buf, nil)),
+				http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					time.Sleep(tt.delay)
+					w.WriteHeader(tt.status)
+				}),
+				slogtool.LoggingOptionStatusLevels(true),
+				slogtool.LoggingOptionSlowRequest(10*time.Millisecond, nil),
+			)
+
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://example.com/test", nil))
+
+			obj := readSingleLogObject(t, buf)
+			if obj["level"] != tt.expect {
+				t.Errorf("level mismatch: got=%v want=%s", obj["level"], tt.expect)
+			}
+
+			httpObj, _ := obj["http"].(map[string]any)
+			if slow, _ := httpObj["slow"].(bool); slow != tt.slow {
+				t.Errorf("http.slow mismatch: got=%v want=%v", httpObj["slow"], tt.slow)
+			}
+		})
+	}
+}
+
+func TestLoggingHTTPHandlerLevelCallback(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	h := slogtool.LoggingHTTPHandler(
+		slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+		slogtool.LoggingOptionLogLevel(slog.LevelError),
+		slogtool.LoggingOptionLevelCallback(func(req *http.Request, status int, _ time.Duration) slog.Level {
+			if req.URL.Path == "/healthz" && status < http.StatusBadRequest {
+				return slog.LevelDebug
+			}
+
+			return slog.LevelInfo
+		}),
+	)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://example.com/healthz", nil))
+
+	obj := readSingleLogObject(t, buf)
+	if obj["level"] != "DEBUG" {
+		t.Errorf("level mismatch: got=%v want=DEBUG", obj["level"])
+	}
+}
+
+func TestLoggingHTTPHandlerPhaseTimings(t *testing.T) {
+	t.Parallel()
+
+	const step = 20 * time.Millisecond
+
+	buf := bytes.NewBuffer(nil)
+	h := slogtool.LoggingHTTPHandler(
+		slog.New(slog.NewJSONHandler(buf, nil)),
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusOK)
+			time.Sleep(step)
+			_, _ = w.Write([]byte("ok"))
+			time.Sleep(step)
+		}),
+	)
+
+	pr, pw := io.Pipe()
+	go func() {
+		time.Sleep(step)
+		_, _ = pw.Write([]byte("body"))
+		_ = pw.Close()
+	}()
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "http://example.com/test", pr))
+
+	httpObj, _ := readSingleLogObject(t, buf)["http"].(map[string]any)
+
+	durations := map[string]time.Duration{}
+	for _, k := range []string{"request_read_time", "ttfb", "duration", "request-time"} {
+		v, ok := httpObj[k].(float64)
+		if !ok {
+			t.Fatalf("expected http.%s, got=%v", k, httpObj)
+		}
+		durations[k] = time.Duration(v)
+	}
+
+	if durations["request_read_time"] < step {
+		t.Errorf("http.request_read_time: got=%s want>=%s", durations["request_read_time"], step)
+	}
+	if durations["ttfb"] < durations["request_read_time"] {
+		t.Errorf("http.ttfb: got=%s want>=%s", durations["ttfb"], durations["request_read_time"])
+	}
+	if durations["duration"] < durations["ttfb"]+step {
+		t.Errorf("http.duration: got=%s want>=%s", durations["duration"], durations["ttfb"]+step)
+	}
+	if durations["request-time"] < durations["duration"]+step {
+		t.Errorf("http.request-time: got=%s want>=%s", durations["request-time"], durations["duration"]+step)
+	}
+}
+
+// slowReader returns one byte of data per Read after a delay.
+type slowReader struct {
+	delay time.Duration
+	n     int
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	if r.n == 0 {
+		return 0, io.EOF
+	}
+
+	time.Sleep(r.delay)
+	r.n--
+	p[0] = 'x'
+
+	return 1, nil
+}
+
+func TestLoggingHTTPHandlerPhaseTimingsReadFrom(t *testing.T) {
+	t.Parallel()
+
+	const step = 20 * time.Millisecond
+
+	buf := bytes.NewBuffer(nil)
+	done := make(chan struct{}, 1)
+	h := slogtool.LoggingHTTPHandler(
+		slog.New(slog.NewJSONHandler(buf, nil)),
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			rf, ok := w.(io.ReaderFrom)
+			if !ok {
+				t.Error("expected the ResponseWriter to implement io.ReaderFrom")
+				return
+			}
+
+			_, _ = rf.ReadFrom(&slowReader{delay: step, n: 5})
+		}),
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r)
+		done <- struct{}{}
+	}))
+	t.Cleanup(srv.Close)
+
+	resp, err := http.Get(srv.URL) //nolint:noctx // test request.
+	if err != nil {
+		t.Fatalf("unable to get: %v", err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	<-done
+
+	httpObj, _ := readSingleLogObject(t, buf)["http"].(map[string]any)
+	ttfb, _ := httpObj["ttfb"].(float64)
+	duration, _ := httpObj["duration"].(float64)
+
+	if time.Duration(duration) < time.Duration(ttfb)+4*step {
+		t.Errorf("http.ttfb: got=%s, want at least %s before http.duration=%s",
+			time.Duration(ttfb), 4*step, time.Duration(duration))
+	}
+}
+
+func TestLoggingHTTPHandlerPhaseTimingsDisabled(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	h := slogtool.LoggingHTTPHandler(
+		slog.New(slog.NewJSONHandler(buf, nil)),
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("ok"))
+		}),
+		slogtool.LoggingOptionTiming(false),
+	)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://example.com/test", nil))
+
+	httpObj, _ := readSingleLogObject(t, buf)["http"].(map[string]any)
+	for _, k := range []string{"request_read_time", "ttfb", "duration"} {
+		if _, ok := httpObj[k]; ok {
+			t.Errorf("http.%s should be omitted when timing is disabled", k)
+		}
+	}
+}
+
+func TestLoggingHTTPHandlerRoute(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /items/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	h := slogtool.LoggingHTTPHandler(
+		slog.New(slog.NewJSONHandler(buf, nil)),
+		mux,
+		slogtool.LoggingOptionIgnoreRoutes("GET /healthz"),
+	)
+
+	for _, target := range []string{"/healthz", "/items/1", "/missing"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://example.com"+target, nil))
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d: %s", len(lines), buf.String())
+	}
+
+	expect := []struct {
+		uri   string
+		route any
+	}{
+		{"http://example.com/items/1", "GET /items/{id}"},
+		{"http://example.com/missing", nil},
+	}
+
+	for i, line := range lines {
+		var obj map[string]any
+		if err := json.Unmarshal([]byte(line), &obj); err != nil {
+			t.Fatalf("unable to parse log line %q: %v", line, err)
+		}
+
+		httpObj, _ := obj["http"].(map[string]any)
+		if httpObj["uri"] != expect[i].uri {
+			t.Errorf("line %d: http.uri mismatch: got=%v want=%v", i, httpObj["uri"], expect[i].uri)
+		}
+
+		if httpObj["route"] != expect[i].route {
+			t.Errorf("line %d: http.route mismatch: got=%v want=%v", i, httpObj["route"], expect[i].route)
+		}
+	}
+}
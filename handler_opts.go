@@ -1,13 +1,18 @@
 package slogtool
 
 import (
+	"io"
 	"log/slog"
 	"net/http"
+	"net/netip"
+	"regexp"
+	"time"
 )
 
 type (
 	LoggingIgnoreRequestCallback   func(req *http.Request) bool
 	LoggingExtractUsernameCallback func(req *http.Request) (string, bool)
+	LoggingLevelCallback           func(req *http.Request, status int, duration time.Duration) slog.Level
 )
 
 type loggingOptions struct {
@@ -17,11 +22,40 @@ type loggingOptions struct {
 	ignoreRequestCallback   LoggingIgnoreRequestCallback
 	extractUsernameCallback LoggingExtractUsernameCallback
 	logLevel                slog.Leveler
+	requestID               bool
+	requestIDHeader         string
+	requestIDGenerator      LoggingRequestIDGenerator
+	traceContext            bool
+	logFormat               *LogFormat
+	logFormatWriter         io.Writer
+	requestHeaders          []string
+	responseHeaders         []string
+	redactHeaders           []string
+	headerMaxLength         int
+	requestBodyLimit        int
+	responseBodyLimit       int
+	bodyContentTypes        []string
+	bodyStatusMin           int
+	bodyStatusMax           int
+	bodyRedactFields        []string
+	bodyLogLevel            slog.Leveler
+	levelCallback           LoggingLevelCallback
+	slowThreshold           time.Duration
+	slowLevel               slog.Leveler
+	recoverPanics           bool
+	repanicAbort            bool
+	trustedProxies          []netip.Prefix
+	clientIPHeader          string
+	metrics                 *HTTPMetrics
+	ignoreRoutes            []string
+	redactQueryParams       []string
+	redactPathPatterns      []*regexp.Regexp
 }
 
 type loggingOptionsFunc func(o *loggingOptions)
 
-// LoggingOptionTiming defines if the logging should contain a `http.request_time` field.
+// LoggingOptionTiming defines if the logging should contain a `http.request_time` field and the
+// `http.ttfb`, `http.duration` and `http.request_read_time` phase timing fields.
 //
 //nolint:revive // deliberately not-exported function type.
 func LoggingOptionTiming(state bool) loggingOptionsFunc {
@@ -67,6 +101,16 @@ func LoggingOptionIgnoreRequest(callback LoggingIgnoreRequestCallback) loggingOp
 	}
 }
 
+// LoggingOptionIgnoreRoutes defines the [http.ServeMux] patterns of the requests that should be ignored in logging,
+// a pattern must be the same as the pattern the handler was registered with (e.g. `GET /healthz`).
+//
+//nolint:revive // deliberately not-exported function type.
+func LoggingOptionIgnoreRoutes(patterns ...string) loggingOptionsFunc {
+	return func(o *loggingOptions) {
+		o.ignoreRoutes = patterns
+	}
+}
+
 // LoggingOptionExtractUsername defines a callback that extracts the username from a request.
 //
 //nolint:revive // deliberately not-exported function type.
@@ -75,3 +119,271 @@ func LoggingOptionExtractUsername(callback LoggingExtractUsernameCallback) loggi
 		o.extractUsernameCallback = callback
 	}
 }
+
+// LoggingOptionRequestID defines if the logging should contain a `http.request_id` field, the request id
+// is read from the header (defaults to [DefaultRequestIDHeader] if empty) or generated if it is missing or invalid,
+// set on the response header and stored in the request context (see [RequestIDFromContext]).
+//
+//nolint:revive // deliberately not-exported function type.
+func LoggingOptionRequestID(state bool, header string) loggingOptionsFunc {
+	return func(o *loggingOptions) {
+		o.requestID = state
+		o.requestIDHeader = header
+	}
+}
+
+// LoggingOptionRequestIDGenerator defines the function used to generate a request id,
+// defaults to [NewRequestID].
+//
+//nolint:revive // deliberately not-exported function type.
+func LoggingOptionRequestIDGenerator(generator LoggingRequestIDGenerator) loggingOptionsFunc {
+	return func(o *loggingOptions) {
+		o.requestIDGenerator = generator
+	}
+}
+
+// LoggingOptionTraceContext defines if the trace context should be parsed from the W3C `traceparent`
+// and `tracestate` headers or the B3 headers, the logging then contains `http.trace_id`, `http.span_id`
+// and `http.trace_flags` fields and the trace context is stored in the request context
+// (see [TraceContextFromContext] and [TraceHandler]).
+//
+//nolint:revive // deliberately not-exported function type.
+func LoggingOptionTraceContext(state bool) loggingOptionsFunc {
+	return func(o *loggingOptions) {
+		o.traceContext = state
+	}
+}
+
+// LoggingOptionLogFormat defines a log format (e.g. [LogFormatCombined]) that requests are written to w in,
+// instead of logging them with the [slog.Logger], a nil writer or format restores the default.
+//
+//nolint:revive // deliberately not-exported function type.
+func LoggingOptionLogFormat(w io.Writer, format *LogFormat) loggingOptionsFunc {
+	return func(o *loggingOptions) {
+		if w == nil || format == nil {
+			o.logFormat, o.logFormatWriter = nil, nil
+			return
+		}
+
+		o.logFormat = format
+		o.logFormatWriter = w
+	}
+}
+
+// LoggingOptionRequestHeaders defines the request headers that should be logged in a `http.request_headers` group.
+//
+//nolint:revive // deliberately not-exported function type.
+func LoggingOptionRequestHeaders(headers ...string) loggingOptionsFunc {
+	return func(o *loggingOptions) {
+		o.requestHeaders = headers
+	}
+}
+
+// LoggingOptionResponseHeaders defines the response headers that should be logged in a `http.response_headers` group.
+//
+//nolint:revive // deliberately not-exported function type.
+func LoggingOptionResponseHeaders(headers ...string) loggingOptionsFunc {
+	return func(o *loggingOptions) {
+		o.responseHeaders = headers
+	}
+}
+
+// LoggingOptionRedactHeaders defines the headers that are logged as [RedactedValue] instead of
+// their value (including `$http_<header>` in a [LogFormat]), defaults to `Authorization`, `Cookie`
+// and `Set-Cookie`.
+//
+//nolint:revive // deliberately not-exported function type.
+func LoggingOptionRedactHeaders(headers ...string) loggingOptionsFunc {
+	return func(o *loggingOptions) {
+		o.redactHeaders = headers
+	}
+}
+
+// LoggingOptionRedactQueryParams defines the query parameters (compared case-insensitively) whose values are
+// logged as [RedactedValue] in the logged URI and referer, defaults to common token, key, password and
+// signature parameters (e.g. `access_token`, `api_key` and `sig`), no arguments disables the redaction.
+//
+// The userinfo of an absolute URL is always logged as [RedactedValue].
+//
+//nolint:revive // deliberately not-exported function type.
+func LoggingOptionRedactQueryParams(params ...string) loggingOptionsFunc {
+	return func(o *loggingOptions) {
+		o.redactQueryParams = params
+	}
+}
+
+// LoggingOptionRedactPathSegments defines the patterns of the path segments that are logged as [RedactedValue]
+// in the logged URI, path and referer, a segment is redacted if any pattern matches it.
+//
+//nolint:revive // deliberately not-exported function type.
+func LoggingOptionRedactPathSegments(patterns ...*regexp.Regexp) loggingOptionsFunc {
+	return func(o *loggingOptions) {
+		o.redactPathPatterns = patterns
+	}
+}
+
+// LoggingOptionHeaderMaxLength defines the maximum length of a logged header value, longer values
+// are truncated, zero (the default) disables truncation.
+//
+//nolint:revive // deliberately not-exported function type.
+func LoggingOptionHeaderMaxLength(length int) loggingOptionsFunc {
+	return func(o *loggingOptions) {
+		o.headerMaxLength = length
+	}
+}
+
+// LoggingOptionRequestBody defines the maximum number of bytes of the request body (as read by the wrapped
+// handler) that should be logged in a `http.request_body` field, zero (the default) disables request body logging.
+//
+// Bodies are logged in a separate `Request Body` record at the body log level (see [LoggingOptionBodyLogLevel]).
+//
+//nolint:revive // deliberately not-exported function type.
+func LoggingOptionRequestBody(maxBytes int) loggingOptionsFunc {
+	return func(o *loggingOptions) {
+		o.requestBodyLimit = maxBytes
+	}
+}
+
+// LoggingOptionResponseBody defines the maximum number of bytes of the response body that should be logged in a
+// `http.response_body` field, zero (the default) disables response body logging.
+//
+// Bodies are logged in a separate `Request Body` record at the body log level (see [LoggingOptionBodyLogLevel]).
+//
+//nolint:revive // deliberately not-exported function type.
+func LoggingOptionResponseBody(maxBytes int) loggingOptionsFunc {
+	return func(o *loggingOptions) {
+		o.responseBodyLimit = maxBytes
+	}
+}
+
+// LoggingOptionBodyContentTypes defines the content types of the bodies that should be logged, a content type
+// matches as a prefix (e.g. `text/` matches `text/plain`), defaults to all content types.
+//
+//nolint:revive // deliberately not-exported function type.
+func LoggingOptionBodyContentTypes(contentTypes ...string) loggingOptionsFunc {
+	return func(o *loggingOptions) {
+		o.bodyContentTypes = contentTypes
+	}
+}
+
+// LoggingOptionBodyStatus defines the range of response status codes (inclusive) that bodies should be logged for,
+// e.g. `500, 599`, defaults to all status codes.
+//
+//nolint:revive // deliberately not-exported function type.
+func LoggingOptionBodyStatus(minStatus, maxStatus int) loggingOptionsFunc {
+	return func(o *loggingOptions) {
+		o.bodyStatusMin = minStatus
+		o.bodyStatusMax = maxStatus
+	}
+}
+
+// LoggingOptionBodyRedactFields defines the JSON fields (at any depth) that are logged as [RedactedValue]
+// instead of their value, a JSON body that can not be parsed (e.g. because it was truncated) is replaced
+// by [RedactedValue].
+//
+//nolint:revive // deliberately not-exported function type.
+func LoggingOptionBodyRedactFields(fields ...string) loggingOptionsFunc {
+	return func(o *loggingOptions) {
+		o.bodyRedactFields = fields
+	}
+}
+
+// LoggingOptionBodyLogLevel defines the log level that bodies should output to, defaults to Debug.
+//
+//nolint:revive // deliberately not-exported function type.
+func LoggingOptionBodyLogLevel(level slog.Leveler) loggingOptionsFunc {
+	return func(o *loggingOptions) {
+		o.bodyLogLevel = level
+	}
+}
+
+// LoggingOptionLevelCallback defines a callback that returns the log level for a request from the response status
+// and request time (e.g. [DefaultStatusLevel]), it overrides the level defined by [LoggingOptionLogLevel].
+//
+//nolint:revive // deliberately not-exported function type.
+func LoggingOptionLevelCallback(callback LoggingLevelCallback) loggingOptionsFunc {
+	return func(o *loggingOptions) {
+		o.levelCallback = callback
+	}
+}
+
+// LoggingOptionStatusLevels defines if the log level should depend on the response status using [DefaultStatusLevel].
+//
+//nolint:revive // deliberately not-exported function type.
+func LoggingOptionStatusLevels(state bool) loggingOptionsFunc {
+	return func(o *loggingOptions) {
+		o.levelCallback = nil
+		if state {
+			o.levelCallback = DefaultStatusLevel
+		}
+	}
+}
+
+// LoggingOptionSlowRequest defines the request time over which a request is slow, slow requests are
+// logged with a `http.slow` field at the higher of their level and the slow level (defaults to Warn if nil),
+// zero (the default) disables slow request escalation.
+//
+//nolint:revive // deliberately not-exported function type.
+func LoggingOptionSlowRequest(threshold time.Duration, level slog.Leveler) loggingOptionsFunc {
+	return func(o *loggingOptions) {
+		if level == nil {
+			level = slog.LevelWarn
+		}
+
+		o.slowThreshold = threshold
+		o.slowLevel = level
+	}
+}
+
+// LoggingOptionRecoverPanics defines if panics in the wrapped handler should be recovered, a recovered panic
+// is logged at Error with the `panic` value and `stack` trace, a `500 Internal Server Error` is written if
+// the header has not been written and the request is logged with status 500.
+//
+//nolint:revive // deliberately not-exported function type.
+func LoggingOptionRecoverPanics(state bool) loggingOptionsFunc {
+	return func(o *loggingOptions) {
+		o.recoverPanics = state
+	}
+}
+
+// LoggingOptionRepanicAbort defines if a recovered [http.ErrAbortHandler] panic should be panicked again after the
+// request is logged, so the server aborts the response, it is not logged as a panic.
+//
+//nolint:revive // deliberately not-exported function type.
+func LoggingOptionRepanicAbort(state bool) loggingOptionsFunc {
+	return func(o *loggingOptions) {
+		o.repanicAbort = state
+	}
+}
+
+// LoggingOptionTrustedProxies defines the proxies trusted to set the client IP header (see
+// [LoggingOptionClientIPHeader]) and [ParseTrustedProxies], the logging will contain a `http.client_ip` field
+// resolved by [ResolveClientIP] and a `http.peer_ip` field, and `$remote_addr` in a log format is the client IP.
+//
+//nolint:revive // deliberately not-exported function type.
+func LoggingOptionTrustedProxies(proxies ...netip.Prefix) loggingOptionsFunc {
+	return func(o *loggingOptions) {
+		o.trustedProxies = proxies
+	}
+}
+
+// LoggingOptionClientIPHeader defines the header set by the trusted proxies that the client IP is resolved from,
+// defaults to `X-Forwarded-For`, it can be `Forwarded` or a single value header (e.g. `X-Real-IP`), other headers
+// are ignored.
+//
+//nolint:revive // deliberately not-exported function type.
+func LoggingOptionClientIPHeader(header string) loggingOptionsFunc {
+	return func(o *loggingOptions) {
+		o.clientIPHeader = header
+	}
+}
+
+// LoggingOptionMetrics defines an [HTTPMetrics] that every request is added to, including requests that are
+// ignored by [LoggingOptionIgnoreRequest] or [LoggingOptionIgnoreRoutes].
+//
+//nolint:revive // deliberately not-exported function type.
+func LoggingOptionMetrics(metrics *HTTPMetrics) loggingOptionsFunc {
+	return func(o *loggingOptions) {
+		o.metrics = metrics
+	}
+}
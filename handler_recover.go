@@ -0,0 +1,58 @@
+package slogtool
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+)
+
+// recoveredPanic is a panic recovered from the wrapped handler and the stack trace where it happened.
+type recoveredPanic struct {
+	value any
+	stack []byte
+}
+
+// serveNext calls the wrapped handler, recovering a panic if [LoggingOptionRecoverPanics] is used.
+func (h loggingHandler) serveNext(w http.ResponseWriter, req *http.Request) (recovered *recoveredPanic) {
+	if h.opts.recoverPanics {
+		defer func() {
+			if v := recover(); v != nil {
+				recovered = &recoveredPanic{value: v, stack: debug.Stack()}
+			}
+		}()
+	}
+
+	h.handler.ServeHTTP(w, req)
+
+	return nil
+}
+
+// isAbort returns true if the panic is [http.ErrAbortHandler].
+func (p *recoveredPanic) isAbort() bool {
+	err, ok := p.value.(error)
+
+	return ok && errors.Is(err, http.ErrAbortHandler)
+}
+
+// writePanicLog logs a recovered panic at error level with the stack trace and request attributes.
+func writePanicLog(ctx context.Context, lh *loggingHandler, req *http.Request, p *recoveredPanic) {
+	attrs := []any{
+		slog.String("method", req.Method),
+		slog.String("uri", lh.opts.logURI(req.RequestURI)),
+	}
+
+	if id, ok := RequestIDFromContext(ctx); ok {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+
+	lh.logger.LogAttrs(
+		ctx,
+		slog.LevelError,
+		"Panic",
+		slog.Any("panic", p.value),
+		slog.String("stack", string(p.stack)),
+		slog.Group("http", attrs...),
+	)
+}
@@ -0,0 +1,120 @@
+package slogtool_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/na4ma4/go-slogtool"
+)
+
+func TestLoggingHTTPHandlerRecoverPanic(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		handler  http.HandlerFunc
+		response int
+	}{
+		{
+			"before-header",
+			func(http.ResponseWriter, *http.Request) {
+				panic("handler panic")
+			},
+			http.StatusInternalServerError,
+		},
+		{
+			"after-header",
+			func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte("partial"))
+				panic("handler panic")
+			},
+			http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := bytes.NewBuffer(nil)
+			h := slogtool.LoggingHTTPHandler(
+				slog.New(slog.NewJSONHandler(buf, nil)),
+				tt.handler,
+				slogtool.LoggingOptionRecoverPanics(true),
+				slogtool.LoggingOptionRequestID(true, ""),
+			)
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/panic", nil)
+			req.Header.Set(slogtool.DefaultRequestIDHeader, "abc-123")
+
+			rw := httptest.NewRecorder()
+			h.ServeHTTP(rw, req)
+
+			if rw.Code != tt.response {
+				t.Errorf("status code mismatch: got=%d want=%d", rw.Code, tt.response)
+			}
+
+			objs := readLogObjects(t, buf)
+			if len(objs) != 2 {
+				t.Fatalf("expected panic and access log lines, got=%v", objs)
+			}
+
+			panicObj, accessObj := objs[0], objs[1]
+
+			if panicObj["msg"] != "Panic" || panicObj["level"] != "ERROR" || panicObj["panic"] != "handler panic" {
+				t.Errorf("unexpected panic log: %v", panicObj)
+			}
+
+			if stack, _ := panicObj["stack"].(string); !strings.Contains(stack, "handler_recover_test.go") {
+				t.Errorf("expected stack trace of the panic, got=%q", stack)
+			}
+
+			if httpObj, _ := panicObj["http"].(map[string]any); httpObj["request_id"] != "abc-123" {
+				t.Errorf("expected request attributes in panic log, got=%v", panicObj["http"])
+			}
+
+			if httpObj, _ := accessObj["http"].(map[string]any); httpObj["status"] != float64(500) {
+				t.Errorf("access log status mismatch: got=%v want=500", httpObj["status"])
+			}
+		})
+	}
+}
+
+func TestLoggingHTTPHandlerRecoverPanicAbort(t *testing.T) {
+	t.Parallel()
+
+	for _, repanic := range []bool{false, true} {
+		buf := bytes.NewBuffer(nil)
+		h := slogtool.LoggingHTTPHandler(
+			slog.New(slog.NewJSONHandler(buf, nil)),
+			http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+				panic(http.ErrAbortHandler)
+			}),
+			slogtool.LoggingOptionRecoverPanics(true),
+			slogtool.LoggingOptionRepanicAbort(repanic),
+		)
+
+		func() {
+			defer func() {
+				r := recover()
+				if repanic && r != http.ErrAbortHandler { //nolint:errorlint // the panic value is compared.
+					t.Errorf("repanic=%v: expected http.ErrAbortHandler panic, got=%v", repanic, r)
+				}
+				if !repanic && r != nil {
+					t.Errorf("repanic=%v: expected no panic, got=%v", repanic, r)
+				}
+			}()
+
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://example.com/abort", nil))
+		}()
+
+		obj := readSingleLogObject(t, buf)
+		if obj["msg"] != "Request" {
+			t.Errorf("repanic=%v: expected only the access log, got=%v", repanic, obj)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package slogtool
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// requestTimings are the phase timings of a request, relative to the start of ServeHTTP.
+type requestTimings struct {
+	start     time.Time
+	firstByte time.Time
+	lastWrite time.Time
+	read      *timingReadCloser
+}
+
+// timingReadCloser is an [io.ReadCloser] that keeps track of the time spent reading the request body.
+type timingReadCloser struct {
+	io.ReadCloser
+
+	elapsed time.Duration
+	read    bool
+}
+
+// Read implements the [io.Reader] interface, it reads from the underlying body and keeps track of the time spent reading.
+func (r *timingReadCloser) Read(p []byte) (int, error) {
+	t := time.Now()
+	n, err := r.ReadCloser.Read(p)
+	r.elapsed += time.Since(t)
+	r.read = true
+
+	return n, err //nolint:wrapcheck // io.EOF must not be wrapped.
+}
+
+// timeRequestBody replaces the body of req with one that keeps track of the time spent reading it,
+// if timing is enabled.
+func (o *loggingOptions) timeRequestBody(req *http.Request) *timingReadCloser {
+	if !o.includeTiming || req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+
+	r := &timingReadCloser{ReadCloser: req.Body}
+	req.Body = r
+
+	return r
+}
+
+// attrs returns the `ttfb`, `duration` and `request_read_time` attributes, `duration` is the time until
+// the last write, or until now if nothing was written.
+func (rt requestTimings) attrs(now time.Time) []any {
+	attrs := make([]any, 0, 3) //nolint:mnd // ttfb, duration and request read time.
+
+	if !rt.firstByte.IsZero() {
+		attrs = append(attrs, slog.Duration("ttfb", rt.firstByte.Sub(rt.start)))
+	}
+
+	end := rt.lastWrite
+	if end.IsZero() {
+		end = now
+	}
+
+	attrs = append(attrs, slog.Duration("duration", end.Sub(rt.start)))
+
+	if rt.read != nil && rt.read.read {
+		attrs = append(attrs, slog.Duration("request_read_time", rt.read.elapsed))
+	}
+
+	return attrs
+}
@@ -0,0 +1,120 @@
+package slogtool
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// defaultRedactQueryParams returns the query parameters redacted by default.
+func defaultRedactQueryParams() []string {
+	return []string{
+		"access_token", "refresh_token", "id_token", "token", "code", "client_secret",
+		"api_key", "apikey", "key", "password", "passwd", "secret", "auth",
+		"sig", "signature", "X-Amz-Signature", "X-Amz-Credential", "X-Amz-Security-Token", "X-Goog-Signature",
+	}
+}
+
+// redactURI returns the URI with the userinfo, the redacted query parameter values and the path segments
+// matching a redacted path pattern replaced by [RedactedValue], the URI is not otherwise re-encoded.
+//
+// The URI can be a request URI (e.g. `/path?query`), an absolute URL, or an authority (e.g. for `CONNECT`).
+func (o *loggingOptions) redactURI(uri string) string {
+	rest, fragment, hasFragment := strings.Cut(uri, "#")
+	rest, query, hasQuery := strings.Cut(rest, "?")
+
+	var out strings.Builder
+
+	path := rest
+	if scheme, after, ok := strings.Cut(rest, "://"); ok {
+		authority, p, hasPath := strings.Cut(after, "/")
+		if i := strings.LastIndexByte(authority, '@'); i >= 0 {
+			authority = RedactedValue + authority[i:]
+		}
+
+		out.WriteString(scheme + "://" + authority)
+
+		path = ""
+		if hasPath {
+			path = "/" + p
+		}
+	}
+
+	out.WriteString(o.redactPath(path))
+
+	if hasQuery {
+		out.WriteByte('?')
+		out.WriteString(o.redactQuery(query))
+	}
+
+	if hasFragment {
+		out.WriteByte('#')
+		out.WriteString(fragment)
+	}
+
+	return out.String()
+}
+
+// redactPath replaces the path segments that match a redacted path pattern.
+func (o *loggingOptions) redactPath(path string) string {
+	if len(o.redactPathPatterns) == 0 {
+		return path
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if segment != "" && matchesAny(o.redactPathPatterns, segment) {
+			segments[i] = RedactedValue
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// redactQuery replaces the values of the redacted query parameters, names are compared case-insensitively.
+func (o *loggingOptions) redactQuery(query string) string {
+	if len(o.redactQueryParams) == 0 {
+		return query
+	}
+
+	params := strings.Split(query, "&")
+	for i, param := range params {
+		rawName, _, hasValue := strings.Cut(param, "=")
+
+		name, err := url.QueryUnescape(rawName)
+		if err != nil {
+			name = rawName
+		}
+
+		if hasValue && o.redactQueryParam(name) {
+			params[i] = rawName + "=" + RedactedValue
+		}
+	}
+
+	return strings.Join(params, "&")
+}
+
+func (o *loggingOptions) redactQueryParam(name string) bool {
+	for _, redact := range o.redactQueryParams {
+		if strings.EqualFold(name, redact) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesAny(patterns []*regexp.Regexp, s string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// logURI returns the redacted and sanitized URI for logging.
+func (o *loggingOptions) logURI(uri string) string {
+	return sanitizeURI(o.redactURI(uri))
+}
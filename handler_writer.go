@@ -0,0 +1,190 @@
+package slogtool
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+)
+
+// countingConn is a [net.Conn] that counts the bytes written to it.
+type countingConn struct {
+	net.Conn
+
+	size *atomic.Int64
+}
+
+// Write implements the [net.Conn] interface, it writes to the underlying connection and counts the bytes written.
+func (c *countingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.size.Add(int64(n))
+
+	return n, err //nolint:wrapcheck // net.Conn errors are passed through.
+}
+
+// countingWriter is an [io.Writer] that counts the bytes written to a hijacked [bufio.Writer], the
+// data is flushed after each write so it is sent when the wrapping writer is flushed.
+type countingWriter struct {
+	w    *bufio.Writer
+	size *atomic.Int64
+}
+
+// Write implements the [io.Writer] interface, it writes to the underlying writer, counts the bytes
+// written and flushes it.
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.size.Add(int64(n))
+
+	if err != nil {
+		return n, err //nolint:wrapcheck // bufio.Writer errors are passed through.
+	}
+
+	return n, c.w.Flush() //nolint:wrapcheck // bufio.Writer errors are passed through.
+}
+
+// unwrapResponseWriter is the [http.ResponseWriter] implemented by every wrapper returned by
+// responseLogger.wrap, Unwrap is used by [http.ResponseController].
+type unwrapResponseWriter interface {
+	http.ResponseWriter
+	Unwrap() http.ResponseWriter
+}
+
+// wrap returns the responseLogger as an [http.ResponseWriter] that implements exactly the optional
+// interfaces ([http.Flusher], [http.Hijacker], [http.Pusher] and [io.ReaderFrom]) that the underlying
+// ResponseWriter implements.
+//
+//nolint:cyclop,funlen // one case for each combination of the optional interfaces.
+func (l *responseLogger) wrap() http.ResponseWriter {
+	const (
+		flusher = 1 << iota
+		hijacker
+		pusher
+		readerFrom
+	)
+
+	var (
+		w  unwrapResponseWriter = l
+		f  http.Flusher         = l
+		hj http.Hijacker        = l
+		p  http.Pusher          = l
+		rf io.ReaderFrom        = l
+	)
+
+	var mask int
+
+	if _, ok := l.w.(http.Flusher); ok {
+		mask |= flusher
+	}
+
+	if _, ok := l.w.(http.Hijacker); ok {
+		mask |= hijacker
+	}
+
+	if _, ok := l.w.(http.Pusher); ok {
+		mask |= pusher
+	}
+
+	if _, ok := l.w.(io.ReaderFrom); ok {
+		mask |= readerFrom
+	}
+
+	switch mask {
+	case flusher:
+		return struct {
+			unwrapResponseWriter
+			http.Flusher
+		}{w, f}
+	case hijacker:
+		return struct {
+			unwrapResponseWriter
+			http.Hijacker
+		}{w, hj}
+	case pusher:
+		return struct {
+			unwrapResponseWriter
+			http.Pusher
+		}{w, p}
+	case readerFrom:
+		return struct {
+			unwrapResponseWriter
+			io.ReaderFrom
+		}{w, rf}
+	case flusher | hijacker:
+		return struct {
+			unwrapResponseWriter
+			http.Flusher
+			http.Hijacker
+		}{w, f, hj}
+	case flusher | pusher:
+		return struct {
+			unwrapResponseWriter
+			http.Flusher
+			http.Pusher
+		}{w, f, p}
+	case flusher | readerFrom:
+		return struct {
+			unwrapResponseWriter
+			http.Flusher
+			io.ReaderFrom
+		}{w, f, rf}
+	case hijacker | pusher:
+		return struct {
+			unwrapResponseWriter
+			http.Hijacker
+			http.Pusher
+		}{w, hj, p}
+	case hijacker | readerFrom:
+		return struct {
+			unwrapResponseWriter
+			http.Hijacker
+			io.ReaderFrom
+		}{w, hj, rf}
+	case pusher | readerFrom:
+		return struct {
+			unwrapResponseWriter
+			http.Pusher
+			io.ReaderFrom
+		}{w, p, rf}
+	case flusher | hijacker | pusher:
+		return struct {
+			unwrapResponseWriter
+			http.Flusher
+			http.Hijacker
+			http.Pusher
+		}{w, f, hj, p}
+	case flusher | hijacker | readerFrom:
+		return struct {
+			unwrapResponseWriter
+			http.Flusher
+			http.Hijacker
+			io.ReaderFrom
+		}{w, f, hj, rf}
+	case flusher | pusher | readerFrom:
+		return struct {
+			unwrapResponseWriter
+			http.Flusher
+			http.Pusher
+			io.ReaderFrom
+		}{w, f, p, rf}
+	case hijacker | pusher | readerFrom:
+		return struct {
+			unwrapResponseWriter
+			http.Hijacker
+			http.Pusher
+			io.ReaderFrom
+		}{w, hj, p, rf}
+	case flusher | hijacker | pusher | readerFrom:
+		return struct {
+			unwrapResponseWriter
+			http.Flusher
+			http.Hijacker
+			http.Pusher
+			io.ReaderFrom
+		}{w, f, hj, p, rf}
+	default:
+		return struct {
+			unwrapResponseWriter
+		}{w}
+	}
+}
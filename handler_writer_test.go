@@ -0,0 +1,237 @@
+package slogtool_test
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/na4ma4/go-slogtool"
+)
+
+// optionalInterfaces returns which of the optional interfaces w implements.
+func optionalInterfaces(w http.ResponseWriter) map[string]bool {
+	_, flusher := w.(http.Flusher)
+	_, hijacker := w.(http.Hijacker)
+	_, pusher := w.(http.Pusher)
+	_, readerFrom := w.(io.ReaderFrom)
+
+	return map[string]bool{
+		"flusher":    flusher,
+		"hijacker":   hijacker,
+		"pusher":     pusher,
+		"readerFrom": readerFrom,
+	}
+}
+
+// plainResponseWriter is a ResponseWriter that implements none of the optional interfaces.
+type plainResponseWriter struct {
+	header http.Header
+	status int
+	buf    bytes.Buffer
+}
+
+func (w *plainResponseWriter) Header() http.Header         { return w.header }
+func (w *plainResponseWriter) Write(b []byte) (int, error) { return w.buf.Write(b) }
+func (w *plainResponseWriter) WriteHeader(status int)      { w.status = status }
+
+// serveLogged serves requests with a LoggingHTTPHandler wrapping next, done is closed after each
+// request has been logged.
+func serveLogged(t *testing.T, buf *bytes.Buffer, next http.Handler) (*httptest.Server, chan struct{}) {
+	t.Helper()
+
+	done := make(chan struct{}, 1)
+	h := slogtool.LoggingHTTPHandler(slog.New(slog.NewJSONHandler(buf, nil)), next,
+		slogtool.LoggingOptionTiming(false),
+		slogtool.LoggingOptionTimestamp(false),
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r)
+		done <- struct{}{}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, done
+}
+
+func TestLoggingHTTPHandlerPreservesInterfaces(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		w    http.ResponseWriter
+	}{
+		{"plain", &plainResponseWriter{header: http.Header{}}},
+		{"recorder", httptest.NewRecorder()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got map[string]bool
+
+			h := slogtool.LoggingHTTPHandler(
+				slog.New(slog.NewJSONHandler(io.Discard, nil)),
+				http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					got = optionalInterfaces(w)
+				}),
+			)
+
+			h.ServeHTTP(tt.w, httptest.NewRequest(http.MethodGet, "http://example.com/", nil))
+
+			want := optionalInterfaces(tt.w)
+			for k, v := range want {
+				if got[k] != v {
+					t.Errorf("%s mismatch: got=%v want=%v", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestLoggingHTTPHandlerServerInterfaces(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	var got map[string]bool
+	var deadlineErr error
+
+	srv, done := serveLogged(t, buf, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		got = optionalInterfaces(w)
+		deadlineErr = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(time.Minute))
+
+		if rf, ok := w.(io.ReaderFrom); ok {
+			_, _ = rf.ReadFrom(strings.NewReader("hello world"))
+		}
+	}))
+
+	resp, err := http.Get(srv.URL + "/readfrom") //nolint:noctx // test request.
+	if err != nil {
+		t.Fatalf("request returned error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	<-done
+
+	if string(body) != "hello world" {
+		t.Errorf("body mismatch: got=%q want=%q", body, "hello world")
+	}
+
+	for k, v := range map[string]bool{"flusher": true, "hijacker": true, "pusher": false, "readerFrom": true} {
+		if got[k] != v {
+			t.Errorf("%s mismatch: got=%v want=%v", k, got[k], v)
+		}
+	}
+
+	if deadlineErr != nil {
+		t.Errorf("ResponseController.SetWriteDeadline returned error: %v", deadlineErr)
+	}
+
+	httpObj, _ := readSingleLogObject(t, buf)["http"].(map[string]any)
+	if httpObj["size"] != float64(len("hello world")) {
+		t.Errorf("http.size mismatch: got=%v want=%d", httpObj["size"], len("hello world"))
+	}
+}
+
+func TestLoggingHTTPHandlerHijack(t *testing.T) {
+	t.Parallel()
+
+	const upgrade = "HTTP/1.1 101 Switching Protocols\r\nUpgrade: test\r\nConnection: Upgrade\r\n\r\nhello"
+
+	buf := bytes.NewBuffer(nil)
+	srv, done := serveLogged(t, buf, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Hijack returned error: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		_, _ = rw.WriteString(upgrade)
+		_ = rw.Flush()
+	}))
+
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("unable to connect: %v", err)
+	}
+	defer conn.Close()
+
+	_, _ = conn.Write([]byte("GET /ws HTTP/1.1\r\nHost: example.com\r\nUpgrade: test\r\nConnection: Upgrade\r\n\r\n"))
+
+	got, _ := io.ReadAll(bufio.NewReader(conn))
+	<-done
+
+	if string(got) != upgrade {
+		t.Errorf("response mismatch: got=%q want=%q", got, upgrade)
+	}
+
+	httpObj, _ := readSingleLogObject(t, buf)["http"].(map[string]any)
+	if httpObj["status"] != float64(http.StatusSwitchingProtocols) {
+		t.Errorf("http.status mismatch: got=%v want=%d", httpObj["status"], http.StatusSwitchingProtocols)
+	}
+	if httpObj["size"] != float64(len(upgrade)) {
+		t.Errorf("http.size mismatch: got=%v want=%d", httpObj["size"], len(upgrade))
+	}
+}
+
+// bufferedHijacker is a ResponseWriter that is hijacked with data already in the buffered writer.
+type bufferedHijacker struct {
+	*plainResponseWriter
+
+	rw *bufio.ReadWriter
+}
+
+func (w *bufferedHijacker) Hijack() (net.Conn, *bufio.ReadWriter, error) { return nil, w.rw, nil }
+
+func TestLoggingHTTPHandlerHijackBuffered(t *testing.T) {
+	t.Parallel()
+
+	const bufferSize = 8192
+
+	out := bytes.NewBuffer(nil)
+	bw := bufio.NewWriterSize(out, bufferSize)
+	_, _ = bw.WriteString("buffered ")
+
+	buf := bytes.NewBuffer(nil)
+	h := slogtool.LoggingHTTPHandler(
+		slog.New(slog.NewJSONHandler(buf, nil)),
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, rw, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Errorf("Hijack returned error: %v", err)
+				return
+			}
+
+			if rw.Writer.Size() != bufferSize {
+				t.Errorf("buffer size mismatch: got=%d want=%d", rw.Writer.Size(), bufferSize)
+			}
+
+			_, _ = rw.WriteString("hello")
+			_ = rw.Flush()
+		}),
+	)
+
+	w := &bufferedHijacker{
+		plainResponseWriter: &plainResponseWriter{header: http.Header{}},
+		rw:                  bufio.NewReadWriter(bufio.NewReader(strings.NewReader("")), bw),
+	}
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ws", nil))
+
+	if out.String() != "buffered hello" {
+		t.Errorf("hijacked output mismatch: got=%q want=%q", out.String(), "buffered hello")
+	}
+
+	httpObj, _ := readSingleLogObject(t, buf)["http"].(map[string]any)
+	if httpObj["size"] != float64(len("hello")) {
+		t.Errorf("http.size mismatch: got=%v want=%d", httpObj["size"], len("hello"))
+	}
+}
@@ -0,0 +1,461 @@
+package slogtool
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrLevelNameRequired is returned when a level request does not specify a logger name.
+	ErrLevelNameRequired = errors.New("logger name is required")
+
+	// ErrLevelInvalid is returned when a level request specifies a level that can not be parsed.
+	ErrLevelInvalid = errors.New("invalid log level")
+
+	// ErrLevelNoMatch is returned when a level request does not match any logger.
+	ErrLevelNoMatch = errors.New("no logger matches name")
+
+	// ErrLevelTTLInvalid is returned when a temporary level override has an invalid or disallowed TTL.
+	ErrLevelTTLInvalid = errors.New("invalid ttl")
+)
+
+// LevelEntry is the JSON representation of a named logger returned by the level handler.
+type LevelEntry struct {
+	Name      string     `json:"name"`
+	Level     string     `json:"level"`
+	Inherited bool       `json:"inherited"`
+	RevertAt  *time.Time `json:"revert_at,omitempty"`
+}
+
+// LevelRequest is the JSON body accepted by the level handler to change the level of a named logger,
+// Name can be a single logger name or a wildcard pattern as accepted by [SlogManager.SetLevel].
+//
+// Level can be a string (`debug`, `info`, `warn`, `error`) or a number, TTL is an optional duration
+// string (e.g. `5m`) after which the matching loggers revert to their previous level.
+type LevelRequest struct {
+	Name  string `json:"name"`
+	Level any    `json:"level"`
+	TTL   string `json:"ttl,omitempty"`
+}
+
+// levelUnsetter is implemented by log managers that support removing an explicitly set level.
+type levelUnsetter interface {
+	UnsetLevel(name string) bool
+}
+
+// levelRuler is implemented by log managers that store level rules for loggers created later.
+type levelRuler interface {
+	LevelRules() LevelSpec
+	AddLevelRule(pattern string, lvl any) bool
+	RemoveLevelRule(pattern string) bool
+}
+
+// defaultLeveler is implemented by log managers that treat the `*` level rule as the default level.
+type defaultLeveler interface {
+	DefaultLevel() slog.Level
+}
+
+// levelRevert stores the state to restore when a temporary level override expires.
+type levelRevert struct {
+	id        uint64
+	level     slog.Level
+	inherited bool
+	created   bool
+	override  slog.Level
+	revertAt  time.Time
+}
+
+// levelHandler is the [http.Handler] implementation for LevelHTTPHandler.
+type levelHandler struct {
+	mgr     LogManager
+	opts    *levelHandlerOptions
+	lock    sync.Mutex
+	nextID  uint64
+	reverts map[string]levelRevert
+	rules   map[string]levelRevert
+}
+
+// LevelHTTPHandler returns a [http.Handler] that lists and modifies the levels of the loggers
+// managed by mgr.
+//
+//   - GET lists the loggers (optionally filtered by the `name` query parameter) as JSON.
+//   - PUT and POST accept a [LevelRequest] JSON body.
+//   - DELETE removes the logger specified by the `name` query parameter.
+func LevelHTTPHandler(mgr LogManager, opts ...levelHandlerOptionsFunc) http.Handler {
+	opt := &levelHandlerOptions{
+		readOnly: false,
+		maxTTL:   0,
+	}
+
+	for _, f := range opts {
+		f(opt)
+	}
+
+	return &levelHandler{
+		mgr:     mgr,
+		opts:    opt,
+		reverts: map[string]levelRevert{},
+		rules:   map[string]levelRevert{},
+	}
+}
+
+// ServeHTTP implements the [http.Handler] interface.
+func (h *levelHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		h.writeJSON(w, http.StatusOK, h.list(req.URL.Query().Get("name")))
+	case http.MethodPut, http.MethodPost:
+		if h.opts.readOnly {
+			h.methodNotAllowed(w)
+			return
+		}
+
+		h.serveSetLevel(w, req)
+	case http.MethodDelete:
+		if h.opts.readOnly {
+			h.methodNotAllowed(w)
+			return
+		}
+
+		h.serveDelete(w, req)
+	default:
+		h.methodNotAllowed(w)
+	}
+}
+
+func (h *levelHandler) serveSetLevel(w http.ResponseWriter, req *http.Request) {
+	var body LevelRequest
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		h.writeError(w, http.StatusBadRequest, fmt.Errorf("unable to decode request: %w", err))
+		return
+	}
+
+	if err := h.setLevel(body); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, ErrLevelNoMatch) {
+			status = http.StatusNotFound
+		}
+
+		h.writeError(w, status, err)
+
+		return
+	}
+
+	if !h.mgr.IsLogger(body.Name) && len(h.list(body.Name)) == 0 {
+		// no logger matches yet, the level is stored as a rule for loggers created later.
+		h.writeJSON(w, http.StatusAccepted, []LevelEntry{})
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, h.list(body.Name))
+}
+
+func (h *levelHandler) serveDelete(w http.ResponseWriter, req *http.Request) {
+	name := req.URL.Query().Get("name")
+	if name == "" {
+		h.writeError(w, http.StatusBadRequest, ErrLevelNameRequired)
+		return
+	}
+
+	if !h.mgr.IsLogger(name) {
+		h.writeError(w, http.StatusNotFound, fmt.Errorf("%w: %s", ErrLevelNoMatch, name))
+		return
+	}
+
+	h.lock.Lock()
+	delete(h.reverts, name)
+	h.lock.Unlock()
+
+	h.mgr.Delete(name)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setLevel applies a level request, scheduling a revert if a TTL is specified.
+func (h *levelHandler) setLevel(body LevelRequest) error {
+	if body.Name == "" {
+		return ErrLevelNameRequired
+	}
+
+	// JSON numbers decode as float64, which slogParseLevel does not accept.
+	if v, ok := body.Level.(float64); ok {
+		body.Level = int(v)
+	}
+
+	level, ok := slogParseLevel(body.Level)
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrLevelInvalid, body.Level)
+	}
+
+	ttl, err := h.parseTTL(body.TTL)
+	if err != nil {
+		return err
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	previous := h.snapshot(body.Name)
+	previousRule, hasPreviousRule := h.currentRule(body.Name)
+
+	if !h.mgr.SetLevel(body.Name, level) && !h.mgr.IsLogger(body.Name) {
+		if _, ok := h.currentRule(body.Name); !ok {
+			return fmt.Errorf("%w: %s", ErrLevelNoMatch, body.Name)
+		}
+	}
+
+	if ttl == 0 {
+		// a permanent change cancels any pending revert.
+		for name := range previous {
+			delete(h.reverts, name)
+		}
+
+		delete(h.rules, body.Name)
+
+		return nil
+	}
+
+	if _, ok := previous[body.Name]; !ok && h.mgr.IsLogger(body.Name) {
+		// SetLevel created an entry for name.
+		previous[body.Name] = levelRevert{created: true}
+	}
+
+	h.nextID++
+	id := h.nextID
+	revertAt := time.Now().Add(ttl)
+
+	for name, prev := range previous {
+		if current, ok := h.currentLevel(name); !ok || current != level {
+			// not changed by SetLevel (e.g. the internal logger).
+			continue
+		}
+
+		if pending, ok := h.reverts[name]; ok {
+			// keep the original level from before the first temporary override.
+			prev.level, prev.inherited, prev.created = pending.level, pending.inherited, pending.created
+		}
+
+		prev.id, prev.override, prev.revertAt = id, level, revertAt
+		h.reverts[name] = prev
+	}
+
+	if current, ok := h.currentRule(body.Name); ok && current == level {
+		prev := levelRevert{level: previousRule, created: !hasPreviousRule}
+		if pending, ok := h.rules[body.Name]; ok {
+			prev.level, prev.created = pending.level, pending.created
+		}
+
+		prev.id, prev.override, prev.revertAt = id, level, revertAt
+		h.rules[body.Name] = prev
+	}
+
+	time.AfterFunc(ttl, func() { h.revert(id) })
+
+	return nil
+}
+
+// parseTTL parses and validates the TTL of a level request.
+func (h *levelHandler) parseTTL(in string) (time.Duration, error) {
+	if in == "" {
+		return 0, nil
+	}
+
+	ttl, err := time.ParseDuration(in)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrLevelTTLInvalid, err)
+	}
+
+	switch {
+	case ttl <= 0:
+		return 0, fmt.Errorf("%w: must be positive: %s", ErrLevelTTLInvalid, in)
+	case h.opts.maxTTL == 0:
+		return 0, fmt.Errorf("%w: temporary overrides are disabled", ErrLevelTTLInvalid)
+	case h.opts.maxTTL > 0 && ttl > h.opts.maxTTL:
+		return 0, fmt.Errorf("%w: exceeds maximum of %s", ErrLevelTTLInvalid, h.opts.maxTTL)
+	}
+
+	return ttl, nil
+}
+
+// snapshot returns the current state of every logger that matches pattern.
+func (h *levelHandler) snapshot(pattern string) map[string]levelRevert {
+	out := map[string]levelRevert{}
+
+	_ = h.mgr.Iterator(func(name string, lvl slog.Leveler) error {
+		if strings.EqualFold(name, pattern) || doesKeyMatchWildcard(name, pattern) {
+			out[name] = levelRevert{
+				level:     lvl.Level(),
+				inherited: isInheritedLeveler(lvl),
+			}
+		}
+
+		return nil
+	})
+
+	return out
+}
+
+// currentLevel returns the current level of the named logger.
+func (h *levelHandler) currentLevel(name string) (slog.Level, bool) {
+	var (
+		out   slog.Level
+		found bool
+	)
+
+	_ = h.mgr.Iterator(func(itemName string, lvl slog.Leveler) error {
+		if itemName == name {
+			out, found = lvl.Level(), true
+		}
+
+		return nil
+	})
+
+	return out, found
+}
+
+// revert restores the levels changed by the temporary override id, unless they have been changed since.
+func (h *levelHandler) revert(id uint64) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for name, prev := range h.reverts {
+		if prev.id != id {
+			continue
+		}
+
+		delete(h.reverts, name)
+
+		if current, ok := h.currentLevel(name); !ok || current != prev.override {
+			continue
+		}
+
+		if prev.created {
+			h.mgr.Delete(name)
+			continue
+		}
+
+		if u, ok := h.mgr.(levelUnsetter); ok && prev.inherited {
+			u.UnsetLevel(name)
+			continue
+		}
+
+		h.mgr.SetLevel(name, prev.level)
+	}
+
+	ruler, ok := h.mgr.(levelRuler)
+	if !ok {
+		return
+	}
+
+	for pattern, prev := range h.rules {
+		if prev.id != id {
+			continue
+		}
+
+		delete(h.rules, pattern)
+
+		if current, ok := h.currentRule(pattern); !ok || current != prev.override {
+			continue
+		}
+
+		if prev.created {
+			ruler.RemoveLevelRule(pattern)
+			continue
+		}
+
+		ruler.AddLevelRule(pattern, prev.level)
+	}
+}
+
+// currentRule returns the level of the level rule with the pattern, if the log manager supports level rules,
+// the rule for `*` is the default level.
+func (h *levelHandler) currentRule(pattern string) (slog.Level, bool) {
+	ruler, ok := h.mgr.(levelRuler)
+	if !ok {
+		return 0, false
+	}
+
+	if d, ok := h.mgr.(defaultLeveler); ok && pattern == "*" {
+		return d.DefaultLevel(), true
+	}
+
+	for _, rule := range ruler.LevelRules() {
+		if strings.EqualFold(rule.Name, pattern) {
+			return rule.Level, true
+		}
+	}
+
+	return 0, false
+}
+
+// list returns the loggers that match pattern (or all loggers if pattern is empty) sorted by name.
+func (h *levelHandler) list(pattern string) []LevelEntry {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	out := []LevelEntry{}
+
+	_ = h.mgr.Iterator(func(name string, lvl slog.Leveler) error {
+		if pattern != "" && !strings.EqualFold(name, pattern) && !doesKeyMatchWildcard(name, pattern) {
+			return nil
+		}
+
+		entry := LevelEntry{
+			Name:      name,
+			Level:     lvl.Level().String(),
+			Inherited: isInheritedLeveler(lvl),
+		}
+
+		if pending, ok := h.reverts[name]; ok {
+			entry.RevertAt = &pending.revertAt
+		}
+
+		out = append(out, entry)
+
+		return nil
+	})
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+
+	return out
+}
+
+func (h *levelHandler) methodNotAllowed(w http.ResponseWriter) {
+	if h.opts.readOnly {
+		w.Header().Set("Allow", strings.Join([]string{http.MethodGet, http.MethodHead}, ", "))
+	} else {
+		w.Header().Set("Allow", strings.Join([]string{
+			http.MethodGet, http.MethodHead, http.MethodPut, http.MethodPost, http.MethodDelete,
+		}, ", "))
+	}
+
+	h.writeError(w, http.StatusMethodNotAllowed, errors.New(http.StatusText(http.StatusMethodNotAllowed)))
+}
+
+func (h *levelHandler) writeError(w http.ResponseWriter, status int, err error) {
+	h.writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func (h *levelHandler) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// isInheritedLeveler returns true if lvl implements [InheritedLeveler] and is inherited.
+func isInheritedLeveler(lvl slog.Leveler) bool {
+	if il, ok := lvl.(InheritedLeveler); ok {
+		return il.Inherited()
+	}
+
+	return false
+}
@@ -0,0 +1,32 @@
+package slogtool
+
+import (
+	"time"
+)
+
+type levelHandlerOptions struct {
+	readOnly bool
+	maxTTL   time.Duration
+}
+
+type levelHandlerOptionsFunc func(o *levelHandlerOptions)
+
+// LevelHandlerOptionReadOnly defines if the level handler should only allow listing the levels,
+// any request that would modify a level returns `405 Method Not Allowed`.
+//
+//nolint:revive // deliberately not-exported function type.
+func LevelHandlerOptionReadOnly(state bool) levelHandlerOptionsFunc {
+	return func(o *levelHandlerOptions) {
+		o.readOnly = state
+	}
+}
+
+// LevelHandlerOptionMaxTTL defines the maximum TTL allowed for a temporary level override,
+// a zero value (the default) disables temporary overrides, a negative value allows any TTL.
+//
+//nolint:revive // deliberately not-exported function type.
+func LevelHandlerOptionMaxTTL(ttl time.Duration) levelHandlerOptionsFunc {
+	return func(o *levelHandlerOptions) {
+		o.maxTTL = ttl
+	}
+}
@@ -0,0 +1,256 @@
+package slogtool_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/na4ma4/go-slogtool"
+)
+
+func newLevelHandlerTestManager(t *testing.T) *slogtool.SlogManager {
+	t.Helper()
+
+	testLog, err := slogtool.NewSlogManager(
+		context.Background(),
+		slogtool.WithWriter(bytes.NewBuffer(nil)),
+		slogtool.WithDefaultLevel(slog.LevelInfo),
+	)
+	if err != nil {
+		t.Fatalf("NewSlogManager returned error: %v", err)
+	}
+
+	_ = testLog.Named("Server")
+	_ = testLog.Named("Server.Process")
+	_ = testLog.Named("Client")
+
+	return testLog
+}
+
+func doLevelRequest(t *testing.T, h http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, req)
+
+	return rw
+}
+
+func decodeLevelEntries(t *testing.T, rw *httptest.ResponseRecorder) map[string]string {
+	t.Helper()
+
+	var entries []slogtool.LevelEntry
+	if err := json.Unmarshal(rw.Body.Bytes(), &entries); err != nil {
+		t.Fatalf("expected JSON level list, got error: %v (%q)", err, rw.Body.String())
+	}
+
+	out := map[string]string{}
+	for _, entry := range entries {
+		out[entry.Name] = entry.Level
+	}
+
+	return out
+}
+
+func TestLevelHTTPHandlerList(t *testing.T) {
+	t.Parallel()
+
+	h := slogtool.LevelHTTPHandler(newLevelHandlerTestManager(t))
+
+	rw := doLevelRequest(t, h, http.MethodGet, "/levels", "")
+	if rw.Code != http.StatusOK {
+		t.Fatalf("status code mismatch: got=%d want=%d", rw.Code, http.StatusOK)
+	}
+	if ct := rw.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("content type mismatch: got=%q", ct)
+	}
+
+	expect := map[string]string{
+		"Client":               "INFO",
+		"Internal.SlogManager": "ERROR+247",
+		"Server":               "INFO",
+		"Server.Process":       "INFO",
+	}
+	if diff := cmp.Diff(decodeLevelEntries(t, rw), expect); diff != "" {
+		t.Errorf("LevelHTTPHandler: list : -got +want:\n%s", diff)
+	}
+
+	rw = doLevelRequest(t, h, http.MethodGet, "/levels?name=Server*", "")
+	expect = map[string]string{
+		"Server":         "INFO",
+		"Server.Process": "INFO",
+	}
+	if diff := cmp.Diff(decodeLevelEntries(t, rw), expect); diff != "" {
+		t.Errorf("LevelHTTPHandler: filtered list : -got +want:\n%s", diff)
+	}
+}
+
+func TestLevelHTTPHandlerSetLevel(t *testing.T) {
+	t.Parallel()
+
+	testLog := newLevelHandlerTestManager(t)
+	h := slogtool.LevelHTTPHandler(testLog)
+
+	rw := doLevelRequest(t, h, http.MethodPut, "/levels", `{"name":"Server","level":"debug"}`)
+	if rw.Code != http.StatusOK {
+		t.Fatalf("status code mismatch: got=%d want=%d (%s)", rw.Code, http.StatusOK, rw.Body.String())
+	}
+
+	expect := "Client:INFO,Internal.SlogManager:ERROR+247,Server.Process:DEBUG,Server:DEBUG"
+	if diff := cmp.Diff(testLog.String(), expect); diff != "" {
+		t.Errorf("LevelHTTPHandler: levels : -got +want:\n%s", diff)
+	}
+
+	rw = doLevelRequest(t, h, http.MethodPost, "/levels", `{"name":"*t","level":8}`)
+	if rw.Code != http.StatusOK {
+		t.Fatalf("status code mismatch: got=%d want=%d (%s)", rw.Code, http.StatusOK, rw.Body.String())
+	}
+
+	expect = "Client:ERROR,Internal.SlogManager:ERROR+247,Server.Process:DEBUG,Server:DEBUG"
+	if diff := cmp.Diff(testLog.String(), expect); diff != "" {
+		t.Errorf("LevelHTTPHandler: levels : -got +want:\n%s", diff)
+	}
+}
+
+func TestLevelHTTPHandlerErrors(t *testing.T) {
+	t.Parallel()
+
+	h := slogtool.LevelHTTPHandler(newLevelHandlerTestManager(t))
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		status int
+	}{
+		{"bad json", http.MethodPut, "/levels", `{`, http.StatusBadRequest},
+		{"missing name", http.MethodPut, "/levels", `{"level":"debug"}`, http.StatusBadRequest},
+		{"invalid level", http.MethodPut, "/levels", `{"name":"Server","level":"loud"}`, http.StatusBadRequest},
+		{"ttl disabled", http.MethodPut, "/levels", `{"name":"Server","level":"debug","ttl":"1m"}`, http.StatusBadRequest},
+		{"delete missing name", http.MethodDelete, "/levels", "", http.StatusBadRequest},
+		{"delete no match", http.MethodDelete, "/levels?name=Nope", "", http.StatusNotFound},
+		{"bad method", http.MethodPatch, "/levels", "", http.StatusMethodNotAllowed},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rw := doLevelRequest(t, h, tc.method, tc.target, tc.body)
+			if rw.Code != tc.status {
+				t.Fatalf("status code mismatch: got=%d want=%d (%s)", rw.Code, tc.status, rw.Body.String())
+			}
+		})
+	}
+}
+
+func TestLevelHTTPHandlerSetLevelPendingRule(t *testing.T) {
+	t.Parallel()
+
+	testLog := newLevelHandlerTestManager(t)
+	h := slogtool.LevelHTTPHandler(testLog)
+
+	rw := doLevelRequest(t, h, http.MethodPut, "/levels", `{"name":"Worker.*","level":"debug"}`)
+	if rw.Code != http.StatusAccepted {
+		t.Fatalf("status code mismatch: got=%d want=%d (%s)", rw.Code, http.StatusAccepted, rw.Body.String())
+	}
+
+	_ = testLog.Named("Worker.One")
+
+	if diff := cmp.Diff(testLog.LevelRules().String(), "Worker.*=DEBUG"); diff != "" {
+		t.Errorf("LevelHTTPHandler: rules : -got +want:\n%s", diff)
+	}
+
+	rw = doLevelRequest(t, h, http.MethodGet, "/levels?name=Worker.*", "")
+	if diff := cmp.Diff(decodeLevelEntries(t, rw), map[string]string{"Worker.One": "DEBUG"}); diff != "" {
+		t.Errorf("LevelHTTPHandler: list : -got +want:\n%s", diff)
+	}
+}
+
+func TestLevelHTTPHandlerDelete(t *testing.T) {
+	t.Parallel()
+
+	testLog := newLevelHandlerTestManager(t)
+	h := slogtool.LevelHTTPHandler(testLog)
+
+	rw := doLevelRequest(t, h, http.MethodDelete, "/levels?name=Client", "")
+	if rw.Code != http.StatusNoContent {
+		t.Fatalf("status code mismatch: got=%d want=%d", rw.Code, http.StatusNoContent)
+	}
+
+	if testLog.IsLogger("Client") {
+		t.Fatal("expected Client to be deleted")
+	}
+}
+
+func TestLevelHTTPHandlerReadOnly(t *testing.T) {
+	t.Parallel()
+
+	h := slogtool.LevelHTTPHandler(newLevelHandlerTestManager(t), slogtool.LevelHandlerOptionReadOnly(true))
+
+	rw := doLevelRequest(t, h, http.MethodPut, "/levels", `{"name":"Server","level":"debug"}`)
+	if rw.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status code mismatch: got=%d want=%d", rw.Code, http.StatusMethodNotAllowed)
+	}
+	if allow := rw.Header().Get("Allow"); allow != "GET, HEAD" {
+		t.Fatalf("allow header mismatch: got=%q", allow)
+	}
+}
+
+func TestLevelHTTPHandlerTTLReverts(t *testing.T) {
+	t.Parallel()
+
+	testLog := newLevelHandlerTestManager(t)
+	testLog.SetLevel("Client", slog.LevelWarn)
+
+	h := slogtool.LevelHTTPHandler(testLog, slogtool.LevelHandlerOptionMaxTTL(time.Minute))
+
+	rw := doLevelRequest(t, h, http.MethodPut, "/levels", `{"name":"*","level":"debug","ttl":"50ms"}`)
+	if rw.Code != http.StatusOK {
+		t.Fatalf("status code mismatch: got=%d want=%d (%s)", rw.Code, http.StatusOK, rw.Body.String())
+	}
+
+	var entries []slogtool.LevelEntry
+	if err := json.Unmarshal(rw.Body.Bytes(), &entries); err != nil {
+		t.Fatalf("expected JSON level list, got error: %v", err)
+	}
+	for _, entry := range entries {
+		if entry.Name != "Internal.SlogManager" && entry.RevertAt == nil {
+			t.Errorf("expected %s to have a revert_at", entry.Name)
+		}
+	}
+
+	expect := "Client:WARN,Internal.SlogManager:ERROR+247,Server.Process:INFO,Server:INFO"
+
+	deadline := time.Now().Add(5 * time.Second)
+	for (testLog.String() != expect || len(testLog.LevelRules()) != 0) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if diff := cmp.Diff(testLog.String(), expect); diff != "" {
+		t.Errorf("LevelHTTPHandler: reverted levels : -got +want:\n%s", diff)
+	}
+
+	if !testLog.IsInherited("Server.Process") {
+		t.Error("expected Server.Process to be inherited after revert")
+	}
+
+	if rules := testLog.LevelRules(); len(rules) != 0 {
+		t.Errorf("expected temporary rule to be removed after revert, got=%s", rules)
+	}
+
+	rw = doLevelRequest(t, h, http.MethodPut, "/levels", `{"name":"Server","level":"debug","ttl":"2m"}`)
+	if rw.Code != http.StatusBadRequest {
+		t.Fatalf("status code mismatch: got=%d want=%d", rw.Code, http.StatusBadRequest)
+	}
+}
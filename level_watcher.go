@@ -0,0 +1,286 @@
+package slogtool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultLevelWatcherInterval = 5 * time.Second
+
+// ErrInvalidLevelFile is returned when a levels file can not be parsed.
+var ErrInvalidLevelFile = errors.New("invalid levels file")
+
+// LevelWatcher loads the levels for a [SlogManager] from a file and re-applies them when the
+// file changes, changes are detected by polling the modification time and size of the file.
+//
+// Files with a `.json` extension contain an object of name (or wildcard pattern) to level,
+// e.g. `{"*": "info", "Server.*": "debug"}`, any other file is read as a level spec (see
+// [ParseLevelSpec]) with entries separated by commas or new lines, lines starting with `#` are ignored.
+//
+// Entries are applied using [SlogManager.SetLevel], least specific pattern first, entries removed
+// from the file are removed using [SlogManager.UnsetLevel]. A `*` entry (or a bare level) sets the default
+// level using [SlogManager.SetDefaultLevel], the same as [WithLevelSpec], removing it restores the
+// default level from before it was applied.
+type LevelWatcher struct {
+	mgr     *SlogManager
+	path    string
+	opts    *levelWatcherOptions
+	lock    sync.Mutex
+	modTime time.Time
+	size    int64
+	current map[string]slog.Level
+
+	failedModTime time.Time
+	failedSize    int64
+
+	previousDefault slog.Level
+}
+
+// NewLevelWatcher returns a new LevelWatcher for the levels file at path.
+func NewLevelWatcher(mgr *SlogManager, path string, opts ...levelWatcherOptionsFunc) *LevelWatcher {
+	opt := &levelWatcherOptions{
+		interval:      defaultLevelWatcherInterval,
+		errorCallback: nil,
+	}
+
+	for _, f := range opts {
+		f(opt)
+	}
+
+	if opt.interval <= 0 {
+		opt.interval = defaultLevelWatcherInterval
+	}
+
+	return &LevelWatcher{
+		mgr:     mgr,
+		path:    path,
+		opts:    opt,
+		current: map[string]slog.Level{},
+	}
+}
+
+// Run loads the levels file and then checks it for changes until ctx is cancelled, errors are
+// logged and reported to the error callback without changing the current levels, an invalid file
+// is reported once until it changes (see [LevelWatcher.Check]).
+func (w *LevelWatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.opts.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.Check(); err != nil {
+			w.reportError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check loads and applies the levels file if it has changed since it was last loaded,
+// returning true if the levels were applied.
+//
+// A file that can not be loaded is only reported once, it is not loaded again until it changes.
+func (w *LevelWatcher) Check() (bool, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return false, fmt.Errorf("unable to stat levels file: %w", err)
+	}
+
+	if info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return false, nil
+	}
+
+	if info.ModTime().Equal(w.failedModTime) && info.Size() == w.failedSize {
+		return false, nil
+	}
+
+	if err := w.loadLocked(); err != nil {
+		w.failedModTime, w.failedSize = info.ModTime(), info.Size()
+		return false, err
+	}
+
+	w.modTime, w.size = info.ModTime(), info.Size()
+	w.failedModTime, w.failedSize = time.Time{}, 0
+
+	return true, nil
+}
+
+// Load loads and applies the levels file, regardless of whether it has changed.
+func (w *LevelWatcher) Load() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return w.loadLocked()
+}
+
+func (w *LevelWatcher) loadLocked() error {
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		return fmt.Errorf("unable to read levels file: %w", err)
+	}
+
+	levels, err := parseLevelFile(w.path, data)
+	if err != nil {
+		return err
+	}
+
+	w.apply(levels)
+
+	return nil
+}
+
+// apply applies the levels and logs the differences from the previously applied levels.
+func (w *LevelWatcher) apply(levels map[string]slog.Level) {
+	for name, lvl := range w.current {
+		if _, ok := levels[name]; !ok {
+			w.mgr.iLogger.Info(
+				"levels file: removed level",
+				slog.String("path", w.path),
+				slog.String("name", name),
+				slog.String("level", lvl.String()),
+			)
+			w.unset(name)
+		}
+	}
+
+	names := make([]string, 0, len(levels))
+	for name := range levels {
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		si, sj := patternSpecificity(names[i]), patternSpecificity(names[j])
+		if si != sj {
+			return si < sj
+		}
+
+		return names[i] < names[j]
+	})
+
+	for _, name := range names {
+		lvl := levels[name]
+
+		switch prev, ok := w.current[name]; {
+		case !ok:
+			w.mgr.iLogger.Info(
+				"levels file: added level",
+				slog.String("path", w.path),
+				slog.String("name", name),
+				slog.String("level", lvl.String()),
+			)
+		case prev != lvl:
+			w.mgr.iLogger.Info(
+				"levels file: changed level",
+				slog.String("path", w.path),
+				slog.String("name", name),
+				slog.String("previous", prev.String()),
+				slog.String("level", lvl.String()),
+			)
+		}
+
+		w.set(name, lvl)
+	}
+
+	w.current = levels
+}
+
+// set applies a level, `*` sets the default level.
+func (w *LevelWatcher) set(name string, lvl slog.Level) {
+	if name != "*" {
+		w.mgr.SetLevel(name, lvl)
+		return
+	}
+
+	if _, ok := w.current[name]; !ok {
+		w.previousDefault = w.mgr.DefaultLevel()
+	}
+
+	w.mgr.SetDefaultLevel(lvl)
+}
+
+// unset removes a level, removing `*` restores the previous default level.
+func (w *LevelWatcher) unset(name string) {
+	if name != "*" {
+		w.mgr.UnsetLevel(name)
+		return
+	}
+
+	w.mgr.SetDefaultLevel(w.previousDefault)
+}
+
+func (w *LevelWatcher) reportError(err error) {
+	w.mgr.iLogger.Error(
+		"levels file: unable to load",
+		slog.String("path", w.path),
+		ErrorAttr(err),
+	)
+
+	if w.opts.errorCallback != nil {
+		w.opts.errorCallback(err)
+	}
+}
+
+// parseLevelFile parses the contents of a levels file, selecting the format by the file extension.
+func parseLevelFile(path string, data []byte) (map[string]slog.Level, error) {
+	out := map[string]slog.Level{}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		var items map[string]any
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidLevelFile, err)
+		}
+
+		for name, v := range items {
+			// JSON numbers decode as float64, which slogParseLevel does not accept.
+			if f, ok := v.(float64); ok {
+				v = int(f)
+			}
+
+			lvl, ok := slogParseLevel(v)
+			if !ok || name == "" {
+				return nil, fmt.Errorf("%w: invalid entry %q: %v", ErrInvalidLevelFile, name, v)
+			}
+
+			out[name] = lvl
+		}
+
+		return out, nil
+	}
+
+	lines := []string{}
+	for line := range strings.SplitSeq(string(data), "\n") {
+		if line = strings.TrimSpace(line); !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+
+	spec, err := ParseLevelSpec(strings.Join(lines, levelSpecSeparator))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidLevelFile, err)
+	}
+
+	for _, entry := range spec {
+		if entry.Name == "" {
+			entry.Name = "*"
+		}
+
+		out[entry.Name] = entry.Level
+	}
+
+	return out, nil
+}
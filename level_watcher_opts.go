@@ -0,0 +1,35 @@
+package slogtool
+
+import (
+	"time"
+)
+
+// LevelWatcherErrorCallback is called when the levels file can not be read or parsed.
+type LevelWatcherErrorCallback func(err error)
+
+type levelWatcherOptions struct {
+	interval      time.Duration
+	errorCallback LevelWatcherErrorCallback
+}
+
+type levelWatcherOptionsFunc func(o *levelWatcherOptions)
+
+// LevelWatcherOptionInterval defines how often the levels file is checked for changes,
+// defaults to 5 seconds.
+//
+//nolint:revive // deliberately not-exported function type.
+func LevelWatcherOptionInterval(interval time.Duration) levelWatcherOptionsFunc {
+	return func(o *levelWatcherOptions) {
+		o.interval = interval
+	}
+}
+
+// LevelWatcherOptionErrorCallback defines a callback that is called when the levels file can not be
+// read or parsed, errors are always logged to the internal logger of the SlogManager.
+//
+//nolint:revive // deliberately not-exported function type.
+func LevelWatcherOptionErrorCallback(callback LevelWatcherErrorCallback) levelWatcherOptionsFunc {
+	return func(o *levelWatcherOptions) {
+		o.errorCallback = callback
+	}
+}
@@ -0,0 +1,271 @@
+package slogtool_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/na4ma4/go-slogtool"
+)
+
+func writeLevelFile(t *testing.T, path, content string, mtime time.Time) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("unable to write levels file: %v", err)
+	}
+
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatalf("unable to set levels file times: %v", err)
+	}
+}
+
+func TestLevelWatcherJSON(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	testLog, _ := slogtool.NewSlogManager(
+		context.Background(),
+		slogtool.WithWriter(buf),
+		slogtool.WithDefaultLevel(slog.LevelInfo),
+		slogtool.WithInternalLevel(slog.LevelInfo),
+	)
+
+	_ = testLog.Named("Server.Process")
+	_ = testLog.Named("Client")
+
+	path := filepath.Join(t.TempDir(), "levels.json")
+	start := time.Now().Add(-time.Hour)
+	writeLevelFile(t, path, `{"Server.*": "debug", "*": "warn", "Client": 8}`, start)
+
+	w := slogtool.NewLevelWatcher(testLog, path)
+
+	if changed, err := w.Check(); err != nil || !changed {
+		t.Fatalf("Check: got changed=%t err=%v, want changed=true err=nil", changed, err)
+	}
+
+	expect := "Client:ERROR,Internal.SlogManager:INFO,Server.Process:DEBUG"
+	if diff := cmp.Diff(testLog.String(), expect); diff != "" {
+		t.Errorf("LevelWatcher: levels : -got +want:\n%s", diff)
+	}
+
+	if changed, err := w.Check(); err != nil || changed {
+		t.Fatalf("Check: got changed=%t err=%v, want changed=false err=nil", changed, err)
+	}
+
+	buf.Reset()
+	writeLevelFile(t, path, `{"*": "warn", "Client": "info"}`, start.Add(time.Minute))
+
+	if changed, err := w.Check(); err != nil || !changed {
+		t.Fatalf("Check: got changed=%t err=%v, want changed=true err=nil", changed, err)
+	}
+
+	expect = "Client:INFO,Internal.SlogManager:INFO,Server.Process:WARN"
+	if diff := cmp.Diff(testLog.String(), expect); diff != "" {
+		t.Errorf("LevelWatcher: levels : -got +want:\n%s", diff)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		`msg="levels file: removed level"` + " path=" + path + " name=Server.* level=DEBUG",
+		`msg="levels file: changed level"` + " path=" + path + " name=Client previous=ERROR level=INFO",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected internal log to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestLevelWatcherSpecFile(t *testing.T) {
+	t.Parallel()
+
+	testLog, _ := slogtool.NewSlogManager(
+		context.Background(),
+		slogtool.WithWriter(bytes.NewBuffer(nil)),
+	)
+
+	path := filepath.Join(t.TempDir(), "levels.conf")
+	writeLevelFile(t, path, "# levels\nwarn\nServer.*=debug,Client=error\n", time.Now())
+
+	if err := slogtool.NewLevelWatcher(testLog, path).Load(); err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	_ = testLog.Named("Server.Process")
+	_ = testLog.Named("Other")
+
+	expect := "Client:ERROR,Internal.SlogManager:ERROR+247,Other:WARN,Server.Process:DEBUG"
+	if diff := cmp.Diff(testLog.String(), expect); diff != "" {
+		t.Errorf("LevelWatcher: levels : -got +want:\n%s", diff)
+	}
+}
+
+func TestLevelWatcherMatchesLevelSpec(t *testing.T) {
+	t.Parallel()
+
+	const spec = "*=warn\nServer=debug"
+
+	fromSpec, err := slogtool.NewSlogManager(
+		context.Background(),
+		slogtool.WithWriter(bytes.NewBuffer(nil)),
+		slogtool.WithDefaultLevel(slog.LevelInfo),
+		slogtool.WithLevelSpec(strings.ReplaceAll(spec, "\n", ",")),
+	)
+	if err != nil {
+		t.Fatalf("NewSlogManager returned error: %v", err)
+	}
+
+	fromFile, _ := slogtool.NewSlogManager(
+		context.Background(),
+		slogtool.WithWriter(bytes.NewBuffer(nil)),
+		slogtool.WithDefaultLevel(slog.LevelInfo),
+	)
+
+	// created before the file is loaded.
+	_ = fromSpec.Named("Server.Process")
+	_ = fromFile.Named("Server.Process")
+
+	path := filepath.Join(t.TempDir(), "levels.conf")
+	writeLevelFile(t, path, spec, time.Now())
+
+	w := slogtool.NewLevelWatcher(fromFile, path)
+	if err := w.Load(); err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	for _, mgr := range []*slogtool.SlogManager{fromSpec, fromFile} {
+		_ = mgr.Named("Server.Other")
+		_ = mgr.Named("Client")
+	}
+
+	expect := "Client:WARN,Internal.SlogManager:ERROR+247,Server.Other:DEBUG,Server.Process:DEBUG,Server:DEBUG"
+	if diff := cmp.Diff(fromSpec.String(), expect); diff != "" {
+		t.Errorf("WithLevelSpec: levels : -got +want:\n%s", diff)
+	}
+	if diff := cmp.Diff(fromFile.String(), fromSpec.String()); diff != "" {
+		t.Errorf("LevelWatcher: levels : -got +want:\n%s", diff)
+	}
+
+	// removing `*` restores the previous default level.
+	writeLevelFile(t, path, "Server=debug", time.Now().Add(time.Minute))
+
+	if err := w.Load(); err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if lvl := fromFile.DefaultLevel(); lvl != slog.LevelInfo {
+		t.Errorf("expected default level to be restored, got=%s", lvl)
+	}
+}
+
+func TestLevelWatcherInvalidKeepsLevels(t *testing.T) {
+	t.Parallel()
+
+	testLog, _ := slogtool.NewSlogManager(
+		context.Background(),
+		slogtool.WithWriter(bytes.NewBuffer(nil)),
+	)
+
+	_ = testLog.Named("Server")
+
+	path := filepath.Join(t.TempDir(), "levels.json")
+	start := time.Now().Add(-time.Hour)
+	writeLevelFile(t, path, `{"Server": "debug"}`, start)
+
+	w := slogtool.NewLevelWatcher(testLog, path)
+	if _, err := w.Check(); err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+
+	for _, content := range []string{`{"Server": `, `{"Server": "loud"}`} {
+		writeLevelFile(t, path, content, start.Add(time.Minute))
+
+		if _, err := w.Check(); !errors.Is(err, slogtool.ErrInvalidLevelFile) {
+			t.Errorf("Check(%q): got err=%v, want ErrInvalidLevelFile", content, err)
+		}
+
+		// the same invalid file is only reported once.
+		if changed, err := w.Check(); err != nil || changed {
+			t.Errorf("Check(%q) again: got changed=%t err=%v, want false, nil", content, changed, err)
+		}
+
+		if lvl := testLog.NewLevel("Server").Level(); lvl != slog.LevelDebug {
+			t.Errorf("expected levels to be unchanged after invalid file, got=%s", lvl)
+		}
+	}
+
+	writeLevelFile(t, path, `{"Server": "warn"}`, start.Add(2*time.Minute))
+
+	if changed, err := w.Check(); err != nil || !changed {
+		t.Errorf("Check after fixing the file: got changed=%t err=%v, want true, nil", changed, err)
+	}
+
+	if lvl := testLog.NewLevel("Server").Level(); lvl != slog.LevelWarn {
+		t.Errorf("expected the fixed levels file to be applied, got=%s", lvl)
+	}
+}
+
+func TestLevelWatcherRun(t *testing.T) {
+	t.Parallel()
+
+	testLog, _ := slogtool.NewSlogManager(
+		context.Background(),
+		slogtool.WithWriter(bytes.NewBuffer(nil)),
+	)
+
+	_ = testLog.Named("Server")
+
+	path := filepath.Join(t.TempDir(), "levels.json")
+
+	var errCount atomic.Int32
+	w := slogtool.NewLevelWatcher(testLog, path,
+		slogtool.LevelWatcherOptionInterval(10*time.Millisecond),
+		slogtool.LevelWatcherOptionErrorCallback(func(_ error) {
+			errCount.Add(1)
+		}),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		w.Run(ctx)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for errCount.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if errCount.Load() == 0 {
+		t.Error("expected error callback to be called for a missing file")
+	}
+
+	writeLevelFile(t, path, `{"Server": "debug"}`, time.Now())
+
+	for testLog.NewLevel("Server").Level() != slog.LevelDebug && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if lvl := testLog.NewLevel("Server").Level(); lvl != slog.LevelDebug {
+		t.Errorf("expected Run to apply the levels file, got=%s", lvl)
+	}
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Run to return after the context is cancelled")
+	}
+}
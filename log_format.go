@@ -0,0 +1,270 @@
+package slogtool
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// LogFormatCommon is the Apache Common Log Format.
+	LogFormatCommon = `$remote_addr - $remote_user [$time_local] "$request" $status $body_bytes_sent_clf`
+
+	// LogFormatCombined is the Apache Combined Log Format.
+	LogFormatCombined = LogFormatCommon + ` "$http_referer" "$http_user_agent"`
+
+	clfTimeFormat   = "02/Jan/2006:15:04:05 -0700"
+	headerVarPrefix = "http_"
+)
+
+// ErrInvalidLogFormat is returned when a log format string can not be parsed.
+var ErrInvalidLogFormat = errors.New("invalid log format")
+
+// accessLogEntry is the request and response details written by a [LogFormat].
+type accessLogEntry struct {
+	opts      *loggingOptions
+	req       *http.Request
+	host      string
+	username  string
+	uri       string
+	referer   string
+	ts        time.Time
+	duration  time.Duration
+	status    int
+	size      int
+	requestID string
+}
+
+// logFormatVar returns the value of a variable for an entry, an empty value is written as `-`.
+type logFormatVar func(e *accessLogEntry) string
+
+//nolint:gochecknoglobals // lookup table of the supported variables.
+var logFormatVars = map[string]logFormatVar{
+	"remote_addr": func(e *accessLogEntry) string { return e.host },
+	"remote_user": func(e *accessLogEntry) string { return e.username },
+	"time_local":  func(e *accessLogEntry) string { return e.ts.Format(clfTimeFormat) },
+	"time_iso8601": func(e *accessLogEntry) string {
+		return e.ts.Format(time.RFC3339)
+	},
+	"request": func(e *accessLogEntry) string {
+		return e.req.Method + " " + e.uri + " " + e.req.Proto
+	},
+	"request_method":  func(e *accessLogEntry) string { return e.req.Method },
+	"request_uri":     func(e *accessLogEntry) string { return e.uri },
+	"server_protocol": func(e *accessLogEntry) string { return e.req.Proto },
+	"host":            func(e *accessLogEntry) string { return e.req.Host },
+	"status":          func(e *accessLogEntry) string { return strconv.Itoa(e.status) },
+	"body_bytes_sent": func(e *accessLogEntry) string { return strconv.Itoa(e.size) },
+	"body_bytes_sent_clf": func(e *accessLogEntry) string {
+		if e.size == 0 {
+			return ""
+		}
+
+		return strconv.Itoa(e.size)
+	},
+	"request_time": func(e *accessLogEntry) string {
+		return strconv.FormatFloat(e.duration.Seconds(), 'f', 3, 64) //nolint:mnd // milliseconds.
+	},
+	"request_id": func(e *accessLogEntry) string { return e.requestID },
+	"route":      func(e *accessLogEntry) string { return e.req.Pattern },
+}
+
+// logFormatSegment is a literal or a variable of a parsed format string.
+type logFormatSegment struct {
+	literal string
+	value   logFormatVar
+}
+
+// LogFormat is a parsed access log format string, similar to the nginx `log_format` directive.
+//
+// The supported variables are `$remote_addr`, `$remote_user`, `$time_local`, `$time_iso8601`, `$request`,
+// `$request_method`, `$request_uri`, `$server_protocol`, `$host`, `$status`, `$body_bytes_sent`,
+// `$body_bytes_sent_clf` (written as `-` instead of `0`, the same as the Apache `%b` used by [LogFormatCommon]),
+// `$request_time`, `$request_id`, `$route` (the [http.ServeMux] pattern that matched the request) and
+// `$http_<header>` (e.g. `$http_user_agent`), a variable name can be enclosed in braces (e.g. `${status}`).
+// The headers redacted by [LoggingOptionRedactHeaders] are written as [RedactedValue].
+//
+// Empty values are written as `-` and values are escaped the same as Apache, quotes, backslashes and
+// non-printable characters are written as escape sequences.
+type LogFormat struct {
+	lock     sync.Mutex
+	segments []logFormatSegment
+}
+
+// ParseLogFormat parses a format string, see [LogFormat] for the supported variables.
+func ParseLogFormat(format string) (*LogFormat, error) {
+	lf := &LogFormat{}
+
+	var literal strings.Builder
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '$' {
+			literal.WriteByte(format[i])
+			continue
+		}
+
+		name, n, err := parseLogFormatVarName(format[i+1:])
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := lookupLogFormatVar(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if literal.Len() > 0 {
+			lf.segments = append(lf.segments, logFormatSegment{literal: literal.String()})
+			literal.Reset()
+		}
+
+		lf.segments = append(lf.segments, logFormatSegment{value: value})
+		i += n
+	}
+
+	if literal.Len() > 0 {
+		lf.segments = append(lf.segments, logFormatSegment{literal: literal.String()})
+	}
+
+	return lf, nil
+}
+
+// MustParseLogFormat is the same as [ParseLogFormat] but panics if the format string can not be parsed.
+func MustParseLogFormat(format string) *LogFormat {
+	lf, err := ParseLogFormat(format)
+	if err != nil {
+		panic(err)
+	}
+
+	return lf
+}
+
+// parseLogFormatVarName returns the variable name at the start of s and the number of bytes it used.
+func parseLogFormatVarName(s string) (string, int, error) {
+	if strings.HasPrefix(s, "{") {
+		end := strings.IndexByte(s, '}')
+		if end < 0 {
+			return "", 0, fmt.Errorf("%w: unterminated variable: ${%s", ErrInvalidLogFormat, s[1:])
+		}
+
+		return s[1:end], end + 1, nil
+	}
+
+	n := 0
+	for n < len(s) && isLogFormatVarChar(s[n]) {
+		n++
+	}
+
+	if n == 0 {
+		return "", 0, fmt.Errorf("%w: missing variable name", ErrInvalidLogFormat)
+	}
+
+	return s[:n], n, nil
+}
+
+func isLogFormatVarChar(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_'
+}
+
+func lookupLogFormatVar(name string) (logFormatVar, error) {
+	name = strings.ToLower(name)
+
+	if value, ok := logFormatVars[name]; ok {
+		return value, nil
+	}
+
+	if header, ok := strings.CutPrefix(name, headerVarPrefix); ok && header != "" {
+		header = http.CanonicalHeaderKey(strings.ReplaceAll(header, "_", "-"))
+
+		if header == "Referer" {
+			return func(e *accessLogEntry) string { return e.referer }, nil
+		}
+
+		return func(e *accessLogEntry) string {
+			if e.opts.isRedactedHeader(header) {
+				return RedactedValue
+			}
+
+			return e.req.Header.Get(header)
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: unknown variable: $%s", ErrInvalidLogFormat, name)
+}
+
+// write writes a line for the entry to w.
+func (lf *LogFormat) write(w io.Writer, e *accessLogEntry) error {
+	var line strings.Builder
+
+	for _, seg := range lf.segments {
+		if seg.value == nil {
+			line.WriteString(seg.literal)
+			continue
+		}
+
+		v := seg.value(e)
+		if v == "" {
+			v = "-"
+		}
+
+		line.WriteString(escapeLogValue(v))
+	}
+
+	line.WriteByte('\n')
+
+	lf.lock.Lock()
+	defer lf.lock.Unlock()
+
+	if _, err := io.WriteString(w, line.String()); err != nil {
+		return fmt.Errorf("unable to write: %w", err)
+	}
+
+	return nil
+}
+
+// logValueEscapes are the control characters written as C escapes by escapeLogValue.
+//
+//nolint:gochecknoglobals // lookup table of the escaped characters.
+var logValueEscapes = [256]byte{'\b': 'b', '\n': 'n', '\r': 'r', '\t': 't', '\v': 'v'}
+
+// escapeLogValue escapes quotes, backslashes and non-printable characters the same as Apache
+// (`ap_escape_logitem`), `\b`, `\n`, `\r`, `\t` and `\v` are written as C escapes, other
+// non-printable characters as `\xhh`.
+func escapeLogValue(v string) string {
+	needsEscape := false
+
+	for i := range len(v) {
+		if c := v[i]; c == '"' || c == '\\' || c < ' ' || c > '~' {
+			needsEscape = true
+			break
+		}
+	}
+
+	if !needsEscape {
+		return v
+	}
+
+	var out strings.Builder
+
+	for i := range len(v) {
+		switch c := v[i]; {
+		case c == '"' || c == '\\':
+			out.WriteByte('\\')
+			out.WriteByte(c)
+		case logValueEscapes[c] != 0:
+			out.WriteByte('\\')
+			out.WriteByte(logValueEscapes[c])
+		case c < ' ' || c > '~':
+			fmt.Fprintf(&out, `\x%02x`, c)
+		default:
+			out.WriteByte(c)
+		}
+	}
+
+	return out.String()
+}
@@ -0,0 +1,225 @@
+package slogtool_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/na4ma4/go-slogtool"
+)
+
+// clfTimeRegexp matches the `[$time_local]` field, replaced as the request time can not be set.
+var clfTimeRegexp = regexp.MustCompile(`\[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\]`)
+
+func serveLogFormat(t *testing.T, format string, req *http.Request, status int, body string) string {
+	t.Helper()
+
+	buf := bytes.NewBuffer(nil)
+	slogBuf := bytes.NewBuffer(nil)
+
+	h := slogtool.LoggingHTTPHandler(
+		slog.New(slog.NewTextHandler(slogBuf, nil)),
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(body))
+		}),
+		slogtool.LoggingOptionLogFormat(buf, slogtool.MustParseLogFormat(format)),
+		slogtool.LoggingOptionExtractUsername(func(req *http.Request) (string, bool) {
+			u := req.Header.Get("X-Logging-Username")
+			return u, u != ""
+		}),
+	)
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	if slogBuf.Len() != 0 {
+		t.Errorf("expected no slog output in log format mode, got=%q", slogBuf.String())
+	}
+
+	return clfTimeRegexp.ReplaceAllString(buf.String(), "[10/Oct/2000:13:55:36 -0700]")
+}
+
+func apacheRequest() *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/apache_pb.gif", nil)
+	req.RemoteAddr = "127.0.0.1:4321"
+	req.Proto, req.ProtoMajor, req.ProtoMinor = "HTTP/1.0", 1, 0
+	req.Header.Set("X-Logging-Username", "frank")
+	req.Header.Set("Referer", "http://www.example.com/start.html")
+	req.Header.Set("User-Agent", "Mozilla/4.08 [en] (Win98; I ;Nav)")
+
+	return req
+}
+
+func TestLoggingHTTPHandlerLogFormatApacheParity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		format string
+		req    func() *http.Request
+		status int
+		size   int
+		expect string
+	}{
+		{
+			"common", slogtool.LogFormatCommon, apacheRequest, http.StatusOK, 2326,
+			`127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326` + "\n",
+		},
+		{
+			"combined", slogtool.LogFormatCombined, apacheRequest, http.StatusOK, 2326,
+			`127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326 ` +
+				`"http://www.example.com/start.html" "Mozilla/4.08 [en] (Win98; I ;Nav)"` + "\n",
+		},
+		{
+			"combined-empty", slogtool.LogFormatCombined,
+			func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/missing", nil)
+				req.RemoteAddr = "192.0.2.1:1234"
+				return req
+			},
+			http.StatusNotFound, 0,
+			`192.0.2.1 - - [10/Oct/2000:13:55:36 -0700] "GET /missing HTTP/1.1" 404 - "-" "-"` + "\n",
+		},
+		{
+			"escaped", slogtool.LogFormatCombined,
+			func() *http.Request {
+				req := apacheRequest()
+				req.Header.Set("User-Agent", "quote\" backslash\\ tab\t newline\n bell\a")
+				return req
+			},
+			http.StatusOK, 1,
+			`127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 1 ` +
+				`"http://www.example.com/start.html" "quote\" backslash\\ tab\t newline\n bell\x07"` + "\n",
+		},
+		{
+			"custom", `$request_method ${request_uri} $status $http_x_custom_header $host`, apacheRequest, http.StatusCreated, 1,
+			"GET /apache_pb.gif 201 - example.com\n",
+		},
+		{
+			"bytes-sent-empty", `$body_bytes_sent $body_bytes_sent_clf`, apacheRequest, http.StatusNoContent, 0,
+			"0 -\n",
+		},
+		{
+			"bytes-sent", `$body_bytes_sent $body_bytes_sent_clf`, apacheRequest, http.StatusOK, 3,
+			"3 3\n",
+		},
+		{
+			"redacted-headers", `"$http_authorization" "$http_cookie" "$http_accept"`,
+			func() *http.Request {
+				req := apacheRequest()
+				req.Header.Set("Authorization", "Bearer secret-token")
+				req.Header.Set("Cookie", "session=secret")
+				req.Header.Set("Accept", "*/*")
+				return req
+			},
+			http.StatusOK, 1,
+			`"[REDACTED]" "[REDACTED]" "*/*"` + "\n",
+		},
+		{
+			"route", `"$route" $status`,
+			func() *http.Request {
+				req := apacheRequest()
+				req.Pattern = "GET /{file}"
+				return req
+			},
+			http.StatusOK, 1,
+			`"GET /{file}" 200` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := serveLogFormat(t, tt.format, tt.req(), tt.status, strings.Repeat("x", tt.size))
+			if diff := cmp.Diff(got, tt.expect); diff != "" {
+				t.Errorf("log format line : -got +want:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoggingHTTPHandlerLogFormatRequestTime(t *testing.T) {
+	t.Parallel()
+
+	got := serveLogFormat(t, `$status $request_time`, apacheRequest(), http.StatusOK, "ok")
+	if !regexp.MustCompile(`^200 \d+\.\d{3}\n$`).MatchString(got) {
+		t.Errorf("unexpected log format line: %q", got)
+	}
+}
+
+func TestLoggingHTTPHandlerLogFormatNil(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		w      io.Writer
+		format *slogtool.LogFormat
+	}{
+		{"nil-writer", nil, slogtool.MustParseLogFormat(slogtool.LogFormatCommon)},
+		{"nil-format", bytes.NewBuffer(nil), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := bytes.NewBuffer(nil)
+			formatBuf := bytes.NewBuffer(nil)
+			h := slogtool.LoggingHTTPHandler(
+				slog.New(slog.NewJSONHandler(buf, nil)),
+				http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					_, _ = w.Write([]byte("ok"))
+				}),
+				slogtool.LoggingOptionLogFormat(formatBuf, slogtool.MustParseLogFormat(slogtool.LogFormatCommon)),
+				slogtool.LoggingOptionLogFormat(tt.w, tt.format),
+			)
+
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+
+			if formatBuf.Len() != 0 {
+				t.Errorf("expected no log format output, got=%q", formatBuf.String())
+			}
+
+			obj := readSingleLogObject(t, buf)
+			if obj["msg"] != "Request" {
+				t.Errorf("msg mismatch: got=%v want=%q", obj["msg"], "Request")
+			}
+		})
+	}
+}
+
+func TestParseLogFormatErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []string{
+		"$unknown",
+		"$ status",
+		"${status",
+		"trailing $",
+	} {
+		if _, err := slogtool.ParseLogFormat(format); !errors.Is(err, slogtool.ErrInvalidLogFormat) {
+			t.Errorf("ParseLogFormat(%q): got error '%v', want '%v'", format, err, slogtool.ErrInvalidLogFormat)
+		}
+	}
+}
+
+func TestMustParseLogFormatPanic(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected MustParseLogFormat to panic")
+		}
+	}()
+
+	_ = slogtool.MustParseLogFormat("$unknown")
+}
@@ -11,6 +11,7 @@ type LogManager interface {
 	IsLogger(name string) bool
 	SetLevel(name string, lvl any) bool
 	Delete(name string)
+	LevelSpec() string
 	String() string
 }
 
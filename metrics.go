@@ -0,0 +1,234 @@
+package slogtool
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMetricsNamespace = "http"
+	metricsContentType      = "text/plain; version=0.0.4; charset=utf-8"
+	otherMethodLabel        = "OTHER"
+)
+
+//nolint:gochecknoglobals // default buckets, copied by NewHTTPMetrics.
+var (
+	// DefaultLatencyBuckets are the default upper bounds in seconds of the request duration histogram.
+	DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+	// DefaultSizeBuckets are the default upper bounds in bytes of the response size histogram.
+	DefaultSizeBuckets = []float64{256, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304}
+)
+
+// HTTPMetricsConfig configures an [HTTPMetrics].
+type HTTPMetricsConfig struct {
+	// Namespace is the prefix of the metric names, defaults to `http`.
+	Namespace string
+	// LatencyBuckets are the upper bounds in seconds of the request duration histogram,
+	// defaults to [DefaultLatencyBuckets].
+	LatencyBuckets []float64
+	// SizeBuckets are the upper bounds in bytes of the response size histogram,
+	// defaults to [DefaultSizeBuckets].
+	SizeBuckets []float64
+	// Route returns the `route` label of a request, defaults to the [http.ServeMux] pattern that
+	// matched the request (empty if the request was not dispatched by a ServeMux).
+	//
+	// The raw path should not be used as the route, each distinct value creates a new series.
+	Route func(req *http.Request) string
+}
+
+// httpMetricsKey is the labels of a series.
+type httpMetricsKey struct {
+	method string
+	route  string
+	status string
+}
+
+// histogram is a cumulative histogram, buckets has one count per upper bound.
+type histogram struct {
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+// httpMetricsSeries is the request duration and response size histograms of a series, the request
+// count is the count of the duration histogram.
+type httpMetricsSeries struct {
+	duration histogram
+	size     histogram
+}
+
+// HTTPMetrics aggregates the requests logged by the HTTP logging handler into request counts and
+// request duration and response size histograms, by method, route and status class (e.g. `2xx`).
+//
+// HTTPMetrics is an [http.Handler] that writes the metrics in the Prometheus text exposition format,
+// it is safe for concurrent use.
+type HTTPMetrics struct {
+	lock   sync.Mutex
+	cfg    HTTPMetricsConfig
+	series map[httpMetricsKey]*httpMetricsSeries
+}
+
+// NewHTTPMetrics returns a new HTTPMetrics, see [LoggingOptionMetrics].
+func NewHTTPMetrics(cfg HTTPMetricsConfig) *HTTPMetrics {
+	if cfg.Namespace == "" {
+		cfg.Namespace = defaultMetricsNamespace
+	}
+
+	if len(cfg.LatencyBuckets) == 0 {
+		cfg.LatencyBuckets = DefaultLatencyBuckets
+	}
+
+	if len(cfg.SizeBuckets) == 0 {
+		cfg.SizeBuckets = DefaultSizeBuckets
+	}
+
+	cfg.LatencyBuckets = slices.Sorted(slices.Values(cfg.LatencyBuckets))
+	cfg.SizeBuckets = slices.Sorted(slices.Values(cfg.SizeBuckets))
+
+	if cfg.Route == nil {
+		cfg.Route = func(req *http.Request) string { return req.Pattern }
+	}
+
+	return &HTTPMetrics{
+		cfg:    cfg,
+		series: map[httpMetricsKey]*httpMetricsSeries{},
+	}
+}
+
+// observe adds a request to the series of its method, route and status class.
+func (m *HTTPMetrics) observe(req *http.Request, status, size int, duration time.Duration) {
+	key := httpMetricsKey{
+		method: metricsMethod(req.Method),
+		route:  m.cfg.Route(req),
+		status: statusClass(status),
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	s, ok := m.series[key]
+	if !ok {
+		s = &httpMetricsSeries{
+			duration: histogram{buckets: make([]uint64, len(m.cfg.LatencyBuckets))},
+			size:     histogram{buckets: make([]uint64, len(m.cfg.SizeBuckets))},
+		}
+		m.series[key] = s
+	}
+
+	s.duration.observe(m.cfg.LatencyBuckets, duration.Seconds())
+	s.size.observe(m.cfg.SizeBuckets, float64(size))
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (m *HTTPMetrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", metricsContentType)
+
+	bw := bufio.NewWriter(w)
+	m.write(bw)
+	_ = bw.Flush()
+}
+
+// write writes the metrics in the Prometheus text exposition format, series are sorted by their labels.
+func (m *HTTPMetrics) write(w *bufio.Writer) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	keys := make([]httpMetricsKey, 0, len(m.series))
+	for key := range m.series {
+		keys = append(keys, key)
+	}
+
+	slices.SortFunc(keys, func(a, b httpMetricsKey) int {
+		return cmp.Or(cmp.Compare(a.method, b.method), cmp.Compare(a.route, b.route), cmp.Compare(a.status, b.status))
+	})
+
+	ns := m.cfg.Namespace
+
+	fmt.Fprintf(w, "# HELP %s_requests_total Total number of HTTP requests.\n", ns)
+	fmt.Fprintf(w, "# TYPE %s_requests_total counter\n", ns)
+
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s_requests_total{%s} %d\n", ns, key.labels(), m.series[key].duration.count)
+	}
+
+	fmt.Fprintf(w, "# HELP %s_request_duration_seconds HTTP request duration in seconds.\n", ns)
+	fmt.Fprintf(w, "# TYPE %s_request_duration_seconds histogram\n", ns)
+
+	for _, key := range keys {
+		m.series[key].duration.write(w, ns+"_request_duration_seconds", key.labels(), m.cfg.LatencyBuckets)
+	}
+
+	fmt.Fprintf(w, "# HELP %s_response_size_bytes HTTP response size in bytes.\n", ns)
+	fmt.Fprintf(w, "# TYPE %s_response_size_bytes histogram\n", ns)
+
+	for _, key := range keys {
+		m.series[key].size.write(w, ns+"_response_size_bytes", key.labels(), m.cfg.SizeBuckets)
+	}
+}
+
+func (h *histogram) observe(bounds []float64, v float64) {
+	for i, bound := range bounds {
+		if v <= bound {
+			h.buckets[i]++
+		}
+	}
+
+	h.sum += v
+	h.count++
+}
+
+// write writes the cumulative buckets, sum and count of the histogram.
+func (h *histogram) write(w *bufio.Writer, name, labels string, bounds []float64) {
+	for i, bound := range bounds {
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"%s\"} %d\n", name, labels, formatMetricValue(bound), h.buckets[i])
+	}
+
+	fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, h.count)
+	fmt.Fprintf(w, "%s_sum{%s} %s\n", name, labels, formatMetricValue(h.sum))
+	fmt.Fprintf(w, "%s_count{%s} %d\n", name, labels, h.count)
+}
+
+func (k httpMetricsKey) labels() string {
+	return fmt.Sprintf(`method="%s",route="%s",status="%s"`,
+		escapeLabelValue(k.method), escapeLabelValue(k.route), escapeLabelValue(k.status),
+	)
+}
+
+// metricsMethod returns the method label, non-standard methods are grouped as `OTHER`.
+func metricsMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	default:
+		return otherMethodLabel
+	}
+}
+
+// statusClass returns the status class label of a status, e.g. `2xx`.
+func statusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "unknown"
+	}
+
+	return strconv.Itoa(status/100) + "xx" //nolint:mnd // status class.
+}
+
+func formatMetricValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+//nolint:gochecknoglobals // replacer for the Prometheus label value escapes.
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(v string) string {
+	return labelValueReplacer.Replace(v)
+}
@@ -0,0 +1,127 @@
+package slogtool_test
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/na4ma4/go-slogtool"
+)
+
+func TestHTTPMetrics(t *testing.T) {
+	t.Parallel()
+
+	metrics := slogtool.NewHTTPMetrics(slogtool.HTTPMetricsConfig{
+		LatencyBuckets: []float64{60},
+		SizeBuckets:    []float64{100, 10},
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /items/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	h := slogtool.LoggingHTTPHandler(
+		slog.New(slog.DiscardHandler),
+		mux,
+		slogtool.LoggingOptionMetrics(metrics),
+		slogtool.LoggingOptionIgnoreRequest(func(req *http.Request) bool {
+			return req.URL.Path == "/items/2"
+		}),
+	)
+
+	for _, r := range []struct{ method, target string }{
+		{http.MethodGet, "/items/1"},
+		{http.MethodGet, "/items/2"},
+		{http.MethodGet, "/missing"},
+		{"PROPFIND", "/items/1"},
+	} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(r.method, "http://example.com"+r.target, nil))
+	}
+
+	rw := httptest.NewRecorder()
+	metrics.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://example.com/metrics", nil))
+
+	if ct := rw.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("content type mismatch: got=%q", ct)
+	}
+
+	// durations are not deterministic.
+	got := regexp.MustCompile(`(?m)^(http_request_duration_seconds_sum\{.*\}) .*$`).
+		ReplaceAllString(rw.Body.String(), "$1 X")
+
+	expect := `# HELP http_requests_total Total number of HTTP requests.
+# TYPE http_requests_total counter
+http_requests_total{method="GET",route="",status="4xx"} 1
+http_requests_total{method="GET",route="GET /items/{id}",status="2xx"} 2
+http_requests_total{method="OTHER",route="",status="4xx"} 1
+# HELP http_request_duration_seconds HTTP request duration in seconds.
+# TYPE http_request_duration_seconds histogram
+http_request_duration_seconds_bucket{method="GET",route="",status="4xx",le="60"} 1
+http_request_duration_seconds_bucket{method="GET",route="",status="4xx",le="+Inf"} 1
+http_request_duration_seconds_sum{method="GET",route="",status="4xx"} X
+http_request_duration_seconds_count{method="GET",route="",status="4xx"} 1
+http_request_duration_seconds_bucket{method="GET",route="GET /items/{id}",status="2xx",le="60"} 2
+http_request_duration_seconds_bucket{method="GET",route="GET /items/{id}",status="2xx",le="+Inf"} 2
+http_request_duration_seconds_sum{method="GET",route="GET /items/{id}",status="2xx"} X
+http_request_duration_seconds_count{method="GET",route="GET /items/{id}",status="2xx"} 2
+http_request_duration_seconds_bucket{method="OTHER",route="",status="4xx",le="60"} 1
+http_request_duration_seconds_bucket{method="OTHER",route="",status="4xx",le="+Inf"} 1
+http_request_duration_seconds_sum{method="OTHER",route="",status="4xx"} X
+http_request_duration_seconds_count{method="OTHER",route="",status="4xx"} 1
+# HELP http_response_size_bytes HTTP response size in bytes.
+# TYPE http_response_size_bytes histogram
+http_response_size_bytes_bucket{method="GET",route="",status="4xx",le="10"} 0
+http_response_size_bytes_bucket{method="GET",route="",status="4xx",le="100"} 1
+http_response_size_bytes_bucket{method="GET",route="",status="4xx",le="+Inf"} 1
+http_response_size_bytes_sum{method="GET",route="",status="4xx"} 19
+http_response_size_bytes_count{method="GET",route="",status="4xx"} 1
+http_response_size_bytes_bucket{method="GET",route="GET /items/{id}",status="2xx",le="10"} 2
+http_response_size_bytes_bucket{method="GET",route="GET /items/{id}",status="2xx",le="100"} 2
+http_response_size_bytes_bucket{method="GET",route="GET /items/{id}",status="2xx",le="+Inf"} 2
+http_response_size_bytes_sum{method="GET",route="GET /items/{id}",status="2xx"} 4
+http_response_size_bytes_count{method="GET",route="GET /items/{id}",status="2xx"} 2
+http_response_size_bytes_bucket{method="OTHER",route="",status="4xx",le="10"} 0
+http_response_size_bytes_bucket{method="OTHER",route="",status="4xx",le="100"} 1
+http_response_size_bytes_bucket{method="OTHER",route="",status="4xx",le="+Inf"} 1
+http_response_size_bytes_sum{method="OTHER",route="",status="4xx"} 19
+http_response_size_bytes_count{method="OTHER",route="",status="4xx"} 1
+`
+
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("metrics mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestHTTPMetricsRouteLabel(t *testing.T) {
+	t.Parallel()
+
+	metrics := slogtool.NewHTTPMetrics(slogtool.HTTPMetricsConfig{
+		Namespace:      "api",
+		LatencyBuckets: []float64{60},
+		SizeBuckets:    []float64{10},
+		Route:          func(*http.Request) string { return "a\"b\\c\nd" },
+	})
+
+	h := slogtool.LoggingHTTPHandler(
+		slog.New(slog.DiscardHandler),
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}),
+		slogtool.LoggingOptionMetrics(metrics),
+	)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "http://example.com/", nil))
+
+	rw := httptest.NewRecorder()
+	metrics.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://example.com/metrics", nil))
+
+	expect := `api_requests_total{method="POST",route="a\"b\\c\nd",status="5xx"} 1`
+	if !strings.Contains(rw.Body.String(), expect+"\n") {
+		t.Errorf("metrics missing %q:\n%s", expect, rw.Body.String())
+	}
+}
@@ -21,6 +21,7 @@ type Handler struct {
 	r func([]string, slog.Attr) slog.Attr
 	b *bytes.Buffer
 	m *sync.Mutex
+	n string
 }
 
 func NewHandler(w io.Writer, opts *slog.HandlerOptions) *Handler {
@@ -52,6 +53,7 @@ func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
 		b: h.b,
 		r: h.r,
 		m: h.m,
+		n: h.n,
 	}
 }
 
@@ -62,6 +64,19 @@ func (h *Handler) WithGroup(name string) slog.Handler {
 		b: h.b,
 		r: h.r,
 		m: h.m,
+		n: h.n,
+	}
+}
+
+// WithName returns a new Handler that renders name as a coloured prefix before the message.
+func (h *Handler) WithName(name string) slog.Handler {
+	return &Handler{
+		w: h.w,
+		h: h.h,
+		b: h.b,
+		r: h.r,
+		m: h.m,
+		n: name,
 	}
 }
 
@@ -168,6 +183,10 @@ func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
 		out.WriteString(level)
 		out.WriteString(" ")
 	}
+	if len(h.n) > 0 {
+		out.WriteString(Colorize(LightGreen, "["+h.n+"]"))
+		out.WriteString(" ")
+	}
 	if len(msg) > 0 {
 		out.WriteString(msg)
 		out.WriteString(" ")
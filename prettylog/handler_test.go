@@ -109,6 +109,30 @@ func TestHandlerWithGroup(t *testing.T) {
 	}
 }
 
+func TestHandlerWithName(t *testing.T) {
+	t.Parallel()
+
+	out := bytes.NewBuffer(nil)
+	h := prettylog.NewHandler(out, &slog.HandlerOptions{Level: slog.LevelDebug})
+
+	named := h.WithName("Server.Process").WithAttrs([]slog.Attr{slog.String("foo", "bar")})
+
+	rec := slog.NewRecord(time.Time{}, slog.LevelInfo, "hello", 0)
+	if err := named.Handle(context.Background(), rec); err != nil {
+		t.Fatalf("Handle returned error: %v", err)
+	}
+
+	got := out.String()
+	prefix := prettylog.Colorize(prettylog.LightGreen, "[Server.Process]") + " " +
+		prettylog.Colorize(prettylog.White, "hello")
+	if !strings.Contains(got, prefix) {
+		t.Fatalf("expected name prefix before message, got=%q", got)
+	}
+	if !strings.Contains(got, `"foo":"bar"`) {
+		t.Fatalf("expected attrs to be preserved, got=%q", got)
+	}
+}
+
 func TestHandlerHandleWithReplaceAttr(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,85 @@
+package slogtool
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+const (
+	// DefaultRequestIDHeader is the header used for the request id by [LoggingOptionRequestID].
+	DefaultRequestIDHeader = "X-Request-ID"
+
+	contextKeyRequestID contextKey = "request_id"
+
+	maxRequestIDLength = 128
+	requestIDBytes     = 16
+)
+
+// LoggingRequestIDGenerator returns a new request id.
+type LoggingRequestIDGenerator func() string
+
+// NewRequestID returns a random 128-bit request id encoded as hex.
+func NewRequestID() string {
+	b := make([]byte, requestIDBytes)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+// ContextWithRequestID returns a copy of ctx with the request id stored in it.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKeyRequestID, id)
+}
+
+// RequestIDFromContext retrieves the request id stored by [ContextWithRequestID] or the HTTP logging handler
+// from the provided context.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	v, ok := ctx.Value(contextKeyRequestID).(string)
+
+	return v, ok && v != ""
+}
+
+// validRequestID returns true if id is a non-empty printable ASCII string without spaces
+// of at most 128 characters.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := range len(id) {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// requestID returns the request id for req, read from the request id header if it is valid, otherwise
+// generated if [LoggingOptionRequestID] is used.
+func (h loggingHandler) requestID(req *http.Request) string {
+	if id := sanitizeURI(req.Header.Get(h.requestIDHeader())); validRequestID(id) {
+		return id
+	}
+
+	if !h.opts.requestID {
+		return ""
+	}
+
+	if h.opts.requestIDGenerator != nil {
+		return sanitizeURI(h.opts.requestIDGenerator())
+	}
+
+	return NewRequestID()
+}
+
+// requestIDHeader returns the header the request id is read from and set on the response.
+func (h loggingHandler) requestIDHeader() string {
+	if h.opts.requestIDHeader == "" {
+		return DefaultRequestIDHeader
+	}
+
+	return h.opts.requestIDHeader
+}
@@ -0,0 +1,113 @@
+package slogtool_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/na4ma4/go-slogtool"
+)
+
+func TestLoggingHTTPHandlerRequestID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		enabled  bool
+		header   string
+		incoming string
+		expect   string
+	}{
+		{"generated", true, "", "", "generated-id"},
+		{"incoming", true, "", "abc-123", "abc-123"},
+		{"custom-header", true, "X-Correlation-ID", "abc-123", "abc-123"},
+		{"invalid-space", true, "", "abc 123", "generated-id"},
+		{"invalid-length", true, "", strings.Repeat("a", 129), "generated-id"},
+		{"sanitized", true, "", "abc\r\n123", "abc123"},
+		{"disabled", false, "", "abc-123", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := bytes.NewBuffer(nil)
+			var inner string
+
+			h := slogtool.LoggingHTTPHandler(
+				slog.New(slog.NewJSONHandler(buf, nil)),
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					inner, _ = slogtool.RequestIDFromContext(r.Context())
+					_, _ = w.Write([]byte("ok"))
+				}),
+				slogtool.LoggingOptionTiming(false),
+				slogtool.LoggingOptionTimestamp(false),
+				slogtool.LoggingOptionRequestID(tt.enabled, tt.header),
+				slogtool.LoggingOptionRequestIDGenerator(func() string { return "generated-id" }),
+			)
+
+			header := tt.header
+			if header == "" {
+				header = slogtool.DefaultRequestIDHeader
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/test", nil)
+			req.RemoteAddr = "127.0.0.1:1234"
+			if tt.incoming != "" {
+				req.Header[http.CanonicalHeaderKey(header)] = []string{tt.incoming}
+			}
+
+			rw := httptest.NewRecorder()
+			h.ServeHTTP(rw, req)
+
+			if got := rw.Header().Get(header); got != tt.expect {
+				t.Errorf("response header mismatch: got=%q want=%q", got, tt.expect)
+			}
+
+			if tt.enabled && inner != tt.expect {
+				t.Errorf("context request id mismatch: got=%q want=%q", inner, tt.expect)
+			}
+
+			obj := readSingleLogObject(t, buf)
+			httpObj, _ := obj["http"].(map[string]any)
+
+			got, ok := httpObj["request_id"].(string)
+			if tt.expect == "" {
+				if ok {
+					t.Errorf("expected no http.request_id, got=%q", got)
+				}
+				return
+			}
+
+			if got != tt.expect {
+				t.Errorf("http.request_id mismatch: got=%q want=%q", got, tt.expect)
+			}
+		})
+	}
+}
+
+func TestNewRequestID(t *testing.T) {
+	t.Parallel()
+
+	a, b := slogtool.NewRequestID(), slogtool.NewRequestID()
+	if len(a) != 32 || a == b {
+		t.Errorf("NewRequestID: expected unique 32 character ids, got=%q and %q", a, b)
+	}
+}
+
+func TestRequestIDFromContext(t *testing.T) {
+	t.Parallel()
+
+	if _, ok := slogtool.RequestIDFromContext(context.Background()); ok {
+		t.Error("RequestIDFromContext: expected no request id")
+	}
+
+	ctx := slogtool.ContextWithRequestID(context.Background(), "abc-123")
+	if got, ok := slogtool.RequestIDFromContext(ctx); !ok || got != "abc-123" {
+		t.Errorf("RequestIDFromContext: got=%q want=%q", got, "abc-123")
+	}
+}
@@ -0,0 +1,408 @@
+package slogtool
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	rotatingFileTimeFormat = "20060102T150405.000000000"
+	rotatingFileMode       = 0o644
+	compressSuffix         = ".gz"
+	backupSeqSeparator     = "-"
+)
+
+// ErrRotatingFileClosed is returned when writing to a [RotatingFile] after it is closed.
+var ErrRotatingFileClosed = errors.New("rotating file closed")
+
+// RotatingFileConfig configures a [RotatingFile].
+type RotatingFileConfig struct {
+	// MaxSize is the size in bytes the file is rotated at, zero disables size based rotation.
+	MaxSize int64
+	// Daily rotates the file on the first write of each day.
+	Daily bool
+	// MaxBackups is the number of rotated files kept, zero keeps all rotated files.
+	MaxBackups int
+	// Compress gzip compresses rotated files in the background.
+	Compress bool
+
+	// Now returns the current time, defaults to [time.Now].
+	Now func() time.Time
+}
+
+// RotatingFile is an [io.WriteCloser] that writes to a file and rotates it by size and/or daily,
+// it is safe for concurrent use.
+//
+// Rotated files are renamed to `<name>-<timestamp><ext>` in the same directory, a `-<n>` sequence
+// number is added to the timestamp if a file was already rotated at the same time.
+type RotatingFile struct {
+	lock     sync.Mutex
+	path     string
+	cfg      RotatingFileConfig
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	closed   bool
+
+	millLock sync.Mutex
+	mill     sync.WaitGroup
+}
+
+// NewRotatingFile returns a new RotatingFile writing to path, the file is created if it does not exist
+// and appended to if it does.
+func NewRotatingFile(path string, cfg RotatingFileConfig) (*RotatingFile, error) {
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+
+	f := &RotatingFile{
+		path: path,
+		cfg:  cfg,
+	}
+
+	if err := f.openLocked(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Write writes p to the file, rotating it first if required.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.closed {
+		return 0, ErrRotatingFileClosed
+	}
+
+	if f.file == nil {
+		if err := f.openLocked(); err != nil {
+			return 0, err
+		}
+	}
+
+	if f.shouldRotateLocked(int64(len(p))) {
+		if err := f.rotateLocked(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+
+	if err != nil {
+		return n, fmt.Errorf("unable to write: %w", err)
+	}
+
+	return n, nil
+}
+
+// Rotate rotates the file regardless of its size or age.
+func (f *RotatingFile) Rotate() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.closed {
+		return ErrRotatingFileClosed
+	}
+
+	return f.rotateLocked()
+}
+
+// Reopen closes and reopens the file, for use after the file has been moved by an external tool
+// (the equivalent of handling SIGHUP).
+func (f *RotatingFile) Reopen() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.closed {
+		return ErrRotatingFileClosed
+	}
+
+	if err := f.closeFileLocked(); err != nil {
+		return err
+	}
+
+	return f.openLocked()
+}
+
+// Close closes the file and waits for any background compression to finish.
+func (f *RotatingFile) Close() error {
+	f.lock.Lock()
+	f.closed = true
+	err := f.closeFileLocked()
+	f.lock.Unlock()
+
+	f.mill.Wait()
+
+	return err
+}
+
+// openLocked opens (or creates) the file at path.
+func (f *RotatingFile) openLocked() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil { //nolint:mnd // standard directory mode.
+		return fmt.Errorf("unable to create log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, rotatingFileMode)
+	if err != nil {
+		return fmt.Errorf("unable to open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("unable to stat log file: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = f.cfg.Now()
+
+	if f.size > 0 {
+		f.openedAt = info.ModTime()
+	}
+
+	return nil
+}
+
+func (f *RotatingFile) closeFileLocked() error {
+	if f.file == nil {
+		return nil
+	}
+
+	err := f.file.Close()
+	f.file = nil
+
+	if err != nil {
+		return fmt.Errorf("unable to close log file: %w", err)
+	}
+
+	return nil
+}
+
+// shouldRotateLocked returns true if writing n bytes requires the file to be rotated first,
+// an empty file is never rotated.
+func (f *RotatingFile) shouldRotateLocked(n int64) bool {
+	if f.size == 0 {
+		return false
+	}
+
+	if f.cfg.MaxSize > 0 && f.size+n > f.cfg.MaxSize {
+		return true
+	}
+
+	if f.cfg.Daily {
+		now := f.cfg.Now()
+		y1, m1, d1 := f.openedAt.In(now.Location()).Date()
+		y2, m2, d2 := now.Date()
+
+		return y1 != y2 || m1 != m2 || d1 != d2
+	}
+
+	return false
+}
+
+// rotateLocked renames the file to a backup name, opens a new file and starts the background
+// compression and removal of old backups.
+func (f *RotatingFile) rotateLocked() error {
+	if err := f.closeFileLocked(); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(f.path); err == nil {
+		if err := os.Rename(f.path, f.backupName(f.cfg.Now())); err != nil {
+			return fmt.Errorf("unable to rename log file: %w", err)
+		}
+	}
+
+	if err := f.openLocked(); err != nil {
+		return err
+	}
+
+	f.mill.Go(f.millRun)
+
+	return nil
+}
+
+// backupName returns an unused name for a file rotated at t, a sequence number is added to the
+// timestamp if a file has already been rotated at the same time (e.g. with a coarse clock).
+func (f *RotatingFile) backupName(t time.Time) string {
+	dir, prefix, ext := f.backupParts()
+	stamp := t.Format(rotatingFileTimeFormat)
+
+	name := filepath.Join(dir, prefix+stamp+ext)
+	for seq := 1; fileExists(name) || fileExists(name+compressSuffix); seq++ {
+		name = filepath.Join(dir, prefix+stamp+backupSeqSeparator+strconv.Itoa(seq)+ext)
+	}
+
+	return name
+}
+
+func fileExists(name string) bool {
+	_, err := os.Lstat(name)
+
+	return err == nil
+}
+
+// parseBackupStamp parses the timestamp and optional sequence number of a rotated file name.
+func parseBackupStamp(stamp string) (time.Time, int, bool) {
+	stamp, rawSeq, hasSeq := strings.Cut(stamp, backupSeqSeparator)
+
+	t, err := time.Parse(rotatingFileTimeFormat, stamp)
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+
+	if !hasSeq {
+		return t, 0, true
+	}
+
+	seq, err := strconv.Atoi(rawSeq)
+	if err != nil || seq <= 0 {
+		return time.Time{}, 0, false
+	}
+
+	return t, seq, true
+}
+
+func (f *RotatingFile) backupParts() (string, string, string) {
+	dir, base := filepath.Split(f.path)
+	ext := filepath.Ext(base)
+
+	return dir, strings.TrimSuffix(base, ext) + "-", ext
+}
+
+// Backups returns the paths of the rotated files, oldest first.
+func (f *RotatingFile) Backups() ([]string, error) {
+	dir, prefix, ext := f.backupParts()
+	if dir == "" {
+		dir = "."
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read log directory: %w", err)
+	}
+
+	type backup struct {
+		name string
+		t    time.Time
+		seq  int
+	}
+
+	found := []backup{}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		stamp, compressed := strings.CutSuffix(name[len(prefix):], compressSuffix)
+
+		t, seq, ok := parseBackupStamp(strings.TrimSuffix(stamp, ext))
+		if !ok {
+			continue
+		}
+
+		// skip a compressed file if the rotated file still exists, the compression is incomplete.
+		if compressed && slices.ContainsFunc(entries, func(e os.DirEntry) bool { return e.Name() == prefix+stamp }) {
+			continue
+		}
+
+		found = append(found, backup{name: filepath.Join(dir, name), t: t, seq: seq})
+	}
+
+	slices.SortFunc(found, func(a, b backup) int {
+		if c := a.t.Compare(b.t); c != 0 {
+			return c
+		}
+
+		return a.seq - b.seq
+	})
+
+	backups := make([]string, 0, len(found))
+	for _, b := range found {
+		backups = append(backups, b.name)
+	}
+
+	return backups, nil
+}
+
+// millRun compresses the rotated files and removes the backups over MaxBackups.
+func (f *RotatingFile) millRun() {
+	f.millLock.Lock()
+	defer f.millLock.Unlock()
+
+	backups, err := f.Backups()
+	if err != nil {
+		return
+	}
+
+	if f.cfg.MaxBackups > 0 && len(backups) > f.cfg.MaxBackups {
+		for _, name := range backups[:len(backups)-f.cfg.MaxBackups] {
+			_ = os.Remove(name)
+		}
+
+		backups = backups[len(backups)-f.cfg.MaxBackups:]
+	}
+
+	if !f.cfg.Compress {
+		return
+	}
+
+	for _, name := range backups {
+		if strings.HasSuffix(name, compressSuffix) {
+			continue
+		}
+
+		if err := compressFile(name); err == nil {
+			_ = os.Remove(name)
+		}
+	}
+}
+
+// compressFile writes a gzip compressed copy of name to name with the compress suffix.
+func compressFile(name string) error {
+	src, err := os.Open(name)
+	if err != nil {
+		return fmt.Errorf("unable to open rotated file: %w", err)
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(name+compressSuffix, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, rotatingFileMode)
+	if err != nil {
+		return fmt.Errorf("unable to create compressed file: %w", err)
+	}
+
+	gz := gzip.NewWriter(dst)
+
+	if _, err := io.Copy(gz, src); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(name + compressSuffix)
+		return fmt.Errorf("unable to compress rotated file: %w", err)
+	}
+
+	if err := gz.Close(); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(name + compressSuffix)
+		return fmt.Errorf("unable to compress rotated file: %w", err)
+	}
+
+	if err := dst.Close(); err != nil {
+		return fmt.Errorf("unable to close compressed file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,245 @@
+package slogtool_test
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/na4ma4/go-slogtool"
+)
+
+func readRotatedFile(t *testing.T, name string) string {
+	t.Helper()
+
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatalf("unable to open '%s': %v", name, err)
+	}
+	defer f.Close()
+
+	var rd io.Reader = f
+	if strings.HasSuffix(name, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatalf("unable to read gzip '%s': %v", name, err)
+		}
+		rd = gz
+	}
+
+	body, err := io.ReadAll(rd)
+	if err != nil {
+		t.Fatalf("unable to read '%s': %v", name, err)
+	}
+
+	return string(body)
+}
+
+func readRotatedFiles(t *testing.T, rf *slogtool.RotatingFile) []string {
+	t.Helper()
+
+	backups, err := rf.Backups()
+	if err != nil {
+		t.Fatalf("Backups returned error: %v", err)
+	}
+
+	out := make([]string, 0, len(backups))
+	for _, name := range backups {
+		out = append(out, readRotatedFile(t, name))
+	}
+
+	return out
+}
+
+func TestRotatingFileSize(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	clock := newTestClock()
+	rf, err := slogtool.NewRotatingFile(path, slogtool.RotatingFileConfig{
+		MaxSize:    10,
+		MaxBackups: 2,
+		Now:        clock.Now,
+	})
+	if err != nil {
+		t.Fatalf("NewRotatingFile returned error: %v", err)
+	}
+
+	for _, line := range []string{"line-1\n", "line-2\n", "line-3\n", "line-4\n"} {
+		if _, err := rf.Write([]byte(line)); err != nil {
+			t.Fatalf("Write returned error: %v", err)
+		}
+		clock.Add(time.Millisecond)
+	}
+
+	if err := rf.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	if diff := cmp.Diff(readRotatedFiles(t, rf), []string{"line-2\n", "line-3\n"}); diff != "" {
+		t.Errorf("RotatingFile: backups : -got +want:\n%s", diff)
+	}
+
+	if diff := cmp.Diff(readRotatedFile(t, path), "line-4\n"); diff != "" {
+		t.Errorf("RotatingFile: current : -got +want:\n%s", diff)
+	}
+
+	if _, err := rf.Write([]byte("closed\n")); !errors.Is(err, slogtool.ErrRotatingFileClosed) {
+		t.Errorf("Write: got error '%v', want '%v'", err, slogtool.ErrRotatingFileClosed)
+	}
+}
+
+func TestRotatingFileSameTimestamp(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	clock := newTestClock()
+	rf, err := slogtool.NewRotatingFile(path, slogtool.RotatingFileConfig{
+		MaxSize: 10,
+		Now:     clock.Now,
+	})
+	if err != nil {
+		t.Fatalf("NewRotatingFile returned error: %v", err)
+	}
+
+	// the clock is not advanced, every rotation gets the same timestamp.
+	for _, line := range []string{"line-1\n", "line-2\n", "line-3\n", "line-4\n"} {
+		if _, err := rf.Write([]byte(line)); err != nil {
+			t.Fatalf("Write returned error: %v", err)
+		}
+	}
+
+	if err := rf.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	if diff := cmp.Diff(readRotatedFiles(t, rf), []string{"line-1\n", "line-2\n", "line-3\n"}); diff != "" {
+		t.Errorf("RotatingFile: backups : -got +want:\n%s", diff)
+	}
+
+	if diff := cmp.Diff(readRotatedFile(t, path), "line-4\n"); diff != "" {
+		t.Errorf("RotatingFile: current : -got +want:\n%s", diff)
+	}
+}
+
+func TestRotatingFileDailyCompress(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	clock := newTestClock()
+	rf, err := slogtool.NewRotatingFile(path, slogtool.RotatingFileConfig{
+		Daily:    true,
+		Compress: true,
+		Now:      clock.Now,
+	})
+	if err != nil {
+		t.Fatalf("NewRotatingFile returned error: %v", err)
+	}
+
+	_, _ = rf.Write([]byte("day-1a\n"))
+	clock.Add(time.Hour)
+	_, _ = rf.Write([]byte("day-1b\n"))
+	clock.Add(24 * time.Hour)
+	_, _ = rf.Write([]byte("day-2\n"))
+
+	if err := rf.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	backups, _ := rf.Backups()
+	if len(backups) != 1 || !strings.HasSuffix(backups[0], ".log.gz") {
+		t.Fatalf("RotatingFile: expected a single compressed backup, got %v", backups)
+	}
+
+	if diff := cmp.Diff(readRotatedFiles(t, rf), []string{"day-1a\nday-1b\n"}); diff != "" {
+		t.Errorf("RotatingFile: backups : -got +want:\n%s", diff)
+	}
+
+	if diff := cmp.Diff(readRotatedFile(t, path), "day-2\n"); diff != "" {
+		t.Errorf("RotatingFile: current : -got +want:\n%s", diff)
+	}
+}
+
+func TestRotatingFileReopen(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	rf, err := slogtool.NewRotatingFile(path, slogtool.RotatingFileConfig{})
+	if err != nil {
+		t.Fatalf("NewRotatingFile returned error: %v", err)
+	}
+	defer rf.Close()
+
+	_, _ = rf.Write([]byte("before\n"))
+
+	if err := os.Rename(path, filepath.Join(dir, "moved.log")); err != nil {
+		t.Fatalf("unable to move log file: %v", err)
+	}
+
+	if err := rf.Reopen(); err != nil {
+		t.Fatalf("Reopen returned error: %v", err)
+	}
+
+	_, _ = rf.Write([]byte("after\n"))
+
+	if diff := cmp.Diff(readRotatedFile(t, filepath.Join(dir, "moved.log")), "before\n"); diff != "" {
+		t.Errorf("RotatingFile: moved : -got +want:\n%s", diff)
+	}
+
+	if diff := cmp.Diff(readRotatedFile(t, path), "after\n"); diff != "" {
+		t.Errorf("RotatingFile: current : -got +want:\n%s", diff)
+	}
+}
+
+func TestSlogManagerWithRotatingFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	rf, err := slogtool.NewRotatingFile(path, slogtool.RotatingFileConfig{})
+	if err != nil {
+		t.Fatalf("NewRotatingFile returned error: %v", err)
+	}
+
+	ctx := context.Background()
+	testLog, _ := slogtool.NewSlogManager(ctx, slogtool.WithWriter(rf))
+
+	done := make(chan struct{})
+	for _, name := range []string{"Server", "Client"} {
+		go func() {
+			defer func() { done <- struct{}{} }()
+
+			logger := testLog.Named(name)
+			for range 50 {
+				logger.InfoContext(ctx, "msg", slog.String("name", name))
+			}
+		}()
+	}
+	<-done
+	<-done
+
+	if err := rf.Rotate(); err != nil {
+		t.Fatalf("Rotate returned error: %v", err)
+	}
+
+	if err := rf.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	rotated := readRotatedFiles(t, rf)
+	if len(rotated) != 1 {
+		t.Fatalf("RotatingFile: expected a single backup, got %d", len(rotated))
+	}
+
+	if got := strings.Count(rotated[0], "\n"); got != 100 {
+		t.Errorf("RotatingFile: line count : got '%d' want '100'", got)
+	}
+}
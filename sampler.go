@@ -0,0 +1,358 @@
+package slogtool
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// SamplingSummaryMessage is the message of the summary record emitted by [SamplingHandler].
+const SamplingSummaryMessage = "log records dropped"
+
+// SamplingConfig configures a [SamplingHandler].
+//
+// Sampling is per level and message: in each Tick the First records are handled, then every
+// Thereafter record (or none if Thereafter is zero). A First of zero disables sampling.
+//
+// Rate limiting is a token bucket of Burst records refilled at Rate records per second. A Rate
+// of zero disables rate limiting.
+//
+// If SummaryInterval is set, a summary record with the dropped counts is emitted at
+// [slog.LevelWarn] by the first record handled after the interval has elapsed. A handler created
+// with [NewSamplingHandler] does not emit a summary while no records are logged, use
+// [SamplingHandler.EmitSummary] to report the dropped counts after a burst. Loggers created by a
+// [SlogManager] (see [WithSampling]) also emit the summary every SummaryInterval from a background
+// goroutine, which is stopped by [SlogManager.Close].
+type SamplingConfig struct {
+	Tick            time.Duration
+	First           int
+	Thereafter      int
+	Rate            float64
+	Burst           int
+	SummaryInterval time.Duration
+
+	// Now returns the current time, defaults to [time.Now].
+	Now func() time.Time
+}
+
+// samplingRoute is a SamplingConfig for loggers with a name that matches pattern.
+type samplingRoute struct {
+	pattern string
+	cfg     SamplingConfig
+}
+
+// samplingStates are the sampling states of the loggers created by a [SlogManager], indexed by
+// logger name, and the background goroutines that emit their summaries.
+type samplingStates struct {
+	lock   sync.Mutex
+	states map[string]*samplingState
+	stop   chan struct{}
+	closed bool
+	wg     sync.WaitGroup
+}
+
+func newSamplingStates() *samplingStates {
+	return &samplingStates{
+		states: map[string]*samplingState{},
+		stop:   make(chan struct{}),
+	}
+}
+
+// get returns the sampling state for name, creating it (and starting its summary goroutine) if
+// it does not exist.
+func (s *samplingStates) get(name string, handler slog.Handler, cfg SamplingConfig) *samplingState {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if state, ok := s.states[name]; ok {
+		return state
+	}
+
+	state := newSamplingState(handler, cfg)
+	s.states[name] = state
+
+	if cfg.SummaryInterval > 0 && !s.closed {
+		s.wg.Add(1)
+
+		go func() {
+			defer s.wg.Done()
+			state.runSummaries(s.stop)
+		}()
+	}
+
+	return state
+}
+
+// close stops the summary goroutines, each emits a final summary.
+func (s *samplingStates) close() {
+	s.lock.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.stop)
+	}
+	s.lock.Unlock()
+
+	s.wg.Wait()
+}
+
+// samplingKey identifies the records sampled together.
+type samplingKey struct {
+	level slog.Level
+	msg   string
+}
+
+// samplingState is shared between a [SamplingHandler] and the handlers returned by WithAttrs and WithGroup.
+type samplingState struct {
+	lock        sync.Mutex
+	cfg         SamplingConfig
+	base        slog.Handler
+	tickStart   time.Time
+	counts      map[samplingKey]int
+	tokens      float64
+	lastRefill  time.Time
+	lastSummary time.Time
+	sampled     int
+	rateLimited int
+}
+
+// SamplingHandler is a [slog.Handler] that samples and rate limits the records passed to the
+// wrapped handler.
+type SamplingHandler struct {
+	handler slog.Handler
+	state   *samplingState
+}
+
+// NewSamplingHandler returns a new SamplingHandler wrapping handler.
+func NewSamplingHandler(handler slog.Handler, cfg SamplingConfig) *SamplingHandler {
+	return &SamplingHandler{
+		handler: handler,
+		state:   newSamplingState(handler, cfg),
+	}
+}
+
+func newSamplingState(handler slog.Handler, cfg SamplingConfig) *samplingState {
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+
+	if cfg.Tick <= 0 {
+		cfg.Tick = time.Second
+	}
+
+	if cfg.Burst < 1 {
+		cfg.Burst = 1
+	}
+
+	now := cfg.Now()
+
+	return &samplingState{
+		cfg:         cfg,
+		base:        handler,
+		tickStart:   now,
+		counts:      map[samplingKey]int{},
+		tokens:      float64(cfg.Burst),
+		lastRefill:  now,
+		lastSummary: now,
+	}
+}
+
+// Enabled reports whether the wrapped handler handles records at the given level.
+func (h *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle passes the record to the wrapped handler if it is not dropped by sampling or rate limiting.
+func (h *SamplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	allow, summary := h.state.allow(r)
+
+	if summary != nil && h.state.base.Enabled(ctx, summary.Level) {
+		if err := h.state.base.Handle(ctx, *summary); err != nil {
+			return err
+		}
+	}
+
+	if !allow {
+		return nil
+	}
+
+	return h.handler.Handle(ctx, r)
+}
+
+// WithAttrs returns a new SamplingHandler sharing the sampling state, wrapping the handler
+// returned by the wrapped handler's WithAttrs.
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SamplingHandler{
+		handler: h.handler.WithAttrs(attrs),
+		state:   h.state,
+	}
+}
+
+// WithGroup returns a new SamplingHandler sharing the sampling state, wrapping the handler
+// returned by the wrapped handler's WithGroup.
+func (h *SamplingHandler) WithGroup(name string) slog.Handler {
+	return &SamplingHandler{
+		handler: h.handler.WithGroup(name),
+		state:   h.state,
+	}
+}
+
+// Dropped returns the number of records dropped by sampling and by rate limiting since the last summary.
+func (h *SamplingHandler) Dropped() (int, int) {
+	h.state.lock.Lock()
+	defer h.state.lock.Unlock()
+
+	return h.state.sampled, h.state.rateLimited
+}
+
+// EmitSummary emits a summary record if any records have been dropped since the last summary.
+func (h *SamplingHandler) EmitSummary(ctx context.Context) error {
+	return h.state.emitSummary(ctx)
+}
+
+// emitSummary emits a summary record if any records have been dropped since the last summary.
+func (s *samplingState) emitSummary(ctx context.Context) error {
+	s.lock.Lock()
+	summary := s.summaryLocked(s.cfg.Now())
+	s.lock.Unlock()
+
+	if summary == nil || !s.base.Enabled(ctx, summary.Level) {
+		return nil
+	}
+
+	return s.base.Handle(ctx, *summary)
+}
+
+// runSummaries emits a summary every SummaryInterval until stop is closed, then emits a final summary.
+func (s *samplingState) runSummaries(stop <-chan struct{}) {
+	ticker := time.NewTicker(s.cfg.SummaryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			_ = s.emitSummary(context.Background())
+			return
+		case <-ticker.C:
+			_ = s.emitSummary(context.Background())
+		}
+	}
+}
+
+// allow returns true if the record should be handled, and a summary record if one is due.
+func (s *samplingState) allow(r slog.Record) (bool, *slog.Record) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.cfg.Now()
+
+	var summary *slog.Record
+	if s.cfg.SummaryInterval > 0 && now.Sub(s.lastSummary) >= s.cfg.SummaryInterval {
+		summary = s.summaryLocked(now)
+	}
+
+	if !s.sampleLocked(now, r) {
+		s.sampled++
+		return false, summary
+	}
+
+	if !s.takeTokenLocked(now) {
+		s.rateLimited++
+		return false, summary
+	}
+
+	return true, summary
+}
+
+// sampleLocked returns true if the record is allowed by sampling.
+func (s *samplingState) sampleLocked(now time.Time, r slog.Record) bool {
+	if s.cfg.First <= 0 {
+		return true
+	}
+
+	if now.Sub(s.tickStart) >= s.cfg.Tick {
+		s.tickStart = now
+		s.counts = map[samplingKey]int{}
+	}
+
+	key := samplingKey{level: r.Level, msg: r.Message}
+	s.counts[key]++
+
+	n := s.counts[key]
+	if n <= s.cfg.First {
+		return true
+	}
+
+	return s.cfg.Thereafter > 0 && (n-s.cfg.First)%s.cfg.Thereafter == 0
+}
+
+// takeTokenLocked returns true if the record is allowed by rate limiting.
+func (s *samplingState) takeTokenLocked(now time.Time) bool {
+	if s.cfg.Rate <= 0 {
+		return true
+	}
+
+	s.tokens += now.Sub(s.lastRefill).Seconds() * s.cfg.Rate
+	s.lastRefill = now
+
+	if burst := float64(s.cfg.Burst); s.tokens > burst {
+		s.tokens = burst
+	}
+
+	if s.tokens < 1 {
+		return false
+	}
+
+	s.tokens--
+
+	return true
+}
+
+// summaryLocked returns a summary record and resets the dropped counts, or nil if nothing was dropped.
+func (s *samplingState) summaryLocked(now time.Time) *slog.Record {
+	s.lastSummary = now
+
+	if s.sampled == 0 && s.rateLimited == 0 {
+		return nil
+	}
+
+	r := slog.NewRecord(now, slog.LevelWarn, SamplingSummaryMessage, 0)
+	r.AddAttrs(
+		slog.Int("sampled", s.sampled),
+		slog.Int("rate_limited", s.rateLimited),
+	)
+
+	s.sampled, s.rateLimited = 0, 0
+
+	return &r
+}
+
+// samplingHandler wraps handler in a [SamplingHandler] if there is a sampling config for name, the
+// sampling state is shared by every logger created with the same name.
+func (a *SlogManager) samplingHandler(name string, handler slog.Handler) slog.Handler {
+	cfg, ok := a.samplingConfig(name)
+	if !ok {
+		return handler
+	}
+
+	return &SamplingHandler{
+		handler: handler,
+		state:   a.samplers.get(name, handler, cfg),
+	}
+}
+
+// samplingConfig returns the sampling config for name, if any.
+func (a *SlogManager) samplingConfig(name string) (SamplingConfig, bool) {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	idx := a.bestPatternMatch(name, len(a.sampling), func(i int) string {
+		return a.sampling[i].pattern
+	})
+
+	if idx < 0 {
+		return SamplingConfig{}, false
+	}
+
+	return a.sampling[idx].cfg, true
+}
@@ -0,0 +1,249 @@
+package slogtool_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/na4ma4/go-slogtool"
+)
+
+type testClock struct {
+	lock sync.Mutex
+	now  time.Time
+}
+
+func newTestClock() *testClock {
+	return &testClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+}
+
+func (c *testClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.now
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func TestSamplingHandlerSampling(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	clock := newTestClock()
+	logger := slog.New(slogtool.NewSamplingHandler(
+		slog.NewTextHandler(buf, nil),
+		slogtool.SamplingConfig{
+			Tick:       time.Second,
+			First:      2,
+			Thereafter: 3,
+			Now:        clock.Now,
+		},
+	))
+
+	for i := range 10 {
+		logger.Info("hot", slog.Int("i", i+1))
+	}
+	logger.Info("other")
+
+	clock.Add(time.Second)
+	logger.Info("hot", slog.Int("i", 11))
+
+	expectLogLines(t, buf, []string{
+		"time=" + timeTestString + " level=INFO msg=hot i=1",
+		"time=" + timeTestString + " level=INFO msg=hot i=2",
+		"time=" + timeTestString + " level=INFO msg=hot i=5",
+		"time=" + timeTestString + " level=INFO msg=hot i=8",
+		"time=" + timeTestString + " level=INFO msg=other",
+		"time=" + timeTestString + " level=INFO msg=hot i=11",
+	})
+}
+
+func TestSamplingHandlerRateLimitAndSummary(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	clock := newTestClock()
+	h := slogtool.NewSamplingHandler(
+		slog.NewTextHandler(buf, nil),
+		slogtool.SamplingConfig{
+			Rate:            1,
+			Burst:           2,
+			SummaryInterval: time.Minute,
+			Now:             clock.Now,
+		},
+	)
+	logger := slog.New(h).With(slog.String("foo", "bar"))
+
+	for i := range 5 {
+		logger.Info("msg" + strconv.Itoa(i+1))
+	}
+
+	clock.Add(time.Second)
+	logger.Info("msg6")
+	logger.Info("msg7")
+
+	if sampled, rateLimited := h.Dropped(); sampled != 0 || rateLimited != 4 {
+		t.Errorf("Dropped: got sampled=%d rate_limited=%d, want 0, 4", sampled, rateLimited)
+	}
+
+	clock.Add(time.Minute)
+	logger.Info("msg8")
+
+	expectLogLines(t, buf, []string{
+		"time=" + timeTestString + " level=INFO msg=msg1 foo=bar",
+		"time=" + timeTestString + " level=INFO msg=msg2 foo=bar",
+		"time=" + timeTestString + " level=INFO msg=msg6 foo=bar",
+		"time=" + timeTestString + ` level=WARN msg="log records dropped" sampled=0 rate_limited=4`,
+		"time=" + timeTestString + " level=INFO msg=msg8 foo=bar",
+	})
+
+	if err := h.EmitSummary(context.Background()); err != nil {
+		t.Fatalf("EmitSummary returned error: %v", err)
+	}
+
+	expectLogLines(t, buf, []string{})
+}
+
+func TestSlogManagerWithSampling(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	clock := newTestClock()
+	ctx := context.Background()
+	testLog, _ := slogtool.NewSlogManager(
+		ctx,
+		slogtool.WithWriter(buf),
+		slogtool.WithSampling("Hot.*", slogtool.SamplingConfig{
+			Tick:  time.Second,
+			First: 1,
+			Now:   clock.Now,
+		}),
+	)
+
+	hot := testLog.Named("Hot.Loop")
+	cold := testLog.Named("Cold")
+
+	for range 3 {
+		hot.InfoContext(ctx, "hot")
+		cold.InfoContext(ctx, "cold")
+	}
+
+	expectLogLines(t, buf, []string{
+		"time=" + timeTestString + " level=INFO msg=hot",
+		"time=" + timeTestString + " level=INFO msg=cold",
+		"time=" + timeTestString + " level=INFO msg=cold",
+		"time=" + timeTestString + " level=INFO msg=cold",
+	})
+}
+
+func TestSlogManagerWithSamplingSharedState(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	clock := newTestClock()
+	ctx := context.Background()
+	testLog, _ := slogtool.NewSlogManager(
+		ctx,
+		slogtool.WithWriter(buf),
+		slogtool.WithSampling("Worker", slogtool.SamplingConfig{
+			Tick:  time.Second,
+			First: 1,
+			Now:   clock.Now,
+		}),
+	)
+
+	// a logger created per request shares the state of the logger name.
+	for range 3 {
+		testLog.Named("Worker").InfoContext(ctx, "hot")
+	}
+
+	expectLogLines(t, buf, []string{
+		"time=" + timeTestString + " level=INFO msg=hot",
+	})
+}
+
+// lockedBuffer is a bytes.Buffer that is safe for concurrent use.
+type lockedBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.buf.String()
+}
+
+// rateLimitedSummaries returns the total of the rate_limited counts of the summary records in out.
+func rateLimitedSummaries(out string) int {
+	total := 0
+
+	for _, m := range regexp.MustCompile(`msg="log records dropped" sampled=0 rate_limited=(\d+)`).FindAllStringSubmatch(out, -1) {
+		n, _ := strconv.Atoi(m[1])
+		total += n
+	}
+
+	return total
+}
+
+func TestSlogManagerWithSamplingSummaryTimer(t *testing.T) {
+	t.Parallel()
+
+	buf := &lockedBuffer{}
+	ctx := context.Background()
+	testLog, _ := slogtool.NewSlogManager(
+		ctx,
+		slogtool.WithWriter(buf),
+		slogtool.WithSampling("Worker", slogtool.SamplingConfig{
+			Rate:            0.001,
+			Burst:           1,
+			SummaryInterval: 10 * time.Millisecond,
+		}),
+	)
+
+	logger := testLog.Named("Worker")
+	for range 3 {
+		logger.InfoContext(ctx, "burst")
+	}
+
+	// the summary is emitted without any further records.
+	deadline := time.Now().Add(5 * time.Second)
+	for rateLimitedSummaries(buf.String()) != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected a summary of 2 rate limited records, got=%q", buf.String())
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	logger.InfoContext(ctx, "burst")
+	logger.InfoContext(ctx, "burst")
+
+	if err := testLog.Close(ctx); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	// the final summary is emitted by Close.
+	if got := rateLimitedSummaries(buf.String()); got != 4 {
+		t.Errorf("rate limited summaries: got=%d want=4, output=%q", got, buf.String())
+	}
+}
@@ -14,22 +14,53 @@ const (
 	slogManagerInternalDefaultLevel = 255
 )
 
+// DefaultLoggerNameKey is the default attribute key used by [WithLoggerName].
+const DefaultLoggerNameKey = "logger"
+
 // SlogManagerOpts is a function type that can be used to configure the SlogManager when creating a new instance.
 type SlogManagerOpts func(*SlogManager) error
 
 // CustomNewHandler is a function type that can be used to provide a custom handler for new sub loggers created by the SlogManager.
 type CustomNewHandler func(name string, w io.Writer, opts *slog.HandlerOptions) slog.Handler
 
+// TextHandler is a CustomNewHandler that returns a [slog.TextHandler].
+func TextHandler(_ string, w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+	return slog.NewTextHandler(w, opts)
+}
+
+// NamedHandler is implemented by handlers that render the logger name themselves, such as
+// prettylog.Handler, it is used by [WithLoggerName] instead of adding an attribute.
+type NamedHandler interface {
+	WithName(name string) slog.Handler
+}
+
+// JSONHandler is a CustomNewHandler that returns a [slog.JSONHandler].
+func JSONHandler(_ string, w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+	return slog.NewJSONHandler(w, opts)
+}
+
 // SlogManager provides a wrapper for multiple [slog.Logger] levels,
 // the individual loggers are not kept, but levels are kept
 // indexed by name.
+//
+// Names are hierarchical, separated by a '.', a logger that has not had its level set
+// explicitly inherits the level of its nearest explicitly set ancestor (e.g. `Server.Process`
+// inherits from `Server`), or the default level if there is none.
 type SlogManager struct {
 	coreNewHandler     CustomNewHandler
 	defaultHandlerOpts *slog.HandlerOptions
 	defaultWriter      io.Writer
 	iLogger            *slog.Logger
 	iLoggerName        string
-	levels             map[string]*slog.LevelVar
+	levels             map[string]*managedLevel
+	levelRules         []LevelSpecEntry
+	loggerNameKey      string
+	routes             []LoggerRoute
+	sampling           []samplingRoute
+	samplers           *samplingStates
+	async              *asyncState
+	sinks              []FanoutSink
+	traceContext       bool
 	lock               sync.RWMutex
 }
 
@@ -62,13 +93,12 @@ func NewSlogManager(opts ...any) (*SlogManager, error) {
 	}
 
 	out := &SlogManager{
-		coreNewHandler: func(_ string, w io.Writer, opts *slog.HandlerOptions) slog.Handler {
-			return slog.NewTextHandler(w, opts)
-		},
+		coreNewHandler:     TextHandler,
 		defaultHandlerOpts: defaultHandlerOpts,
 		defaultWriter:      defaultWriter,
 		iLoggerName:        defaultSlogManagerInternalName,
-		levels:             map[string]*slog.LevelVar{},
+		levels:             map[string]*managedLevel{},
+		samplers:           newSamplingStates(),
 		lock:               sync.RWMutex{},
 	}
 
@@ -81,8 +111,7 @@ func NewSlogManager(opts ...any) (*SlogManager, error) {
 	}
 
 	if _, ok := out.levels[out.iLoggerName]; !ok {
-		out.levels[out.iLoggerName] = new(slog.LevelVar)
-		out.levels[out.iLoggerName].Set(slogManagerInternalDefaultLevel)
+		out.levels[out.iLoggerName] = newManagedLevel(slogManagerInternalDefaultLevel, true)
 	}
 
 	out.iLogger = out.Named(out.iLoggerName)
@@ -91,13 +120,15 @@ func NewSlogManager(opts ...any) (*SlogManager, error) {
 }
 
 // NewLevel returns as a log.Leveler reference to the stored named level.
+//
+// A new level is set by the last matching level rule (see [WithLevelSpec]), otherwise it inherits
+// the level of its nearest explicitly set ancestor, or the default level.
 func (a *SlogManager) NewLevel(name string) slog.Leveler {
 	a.lock.Lock()
 	defer a.lock.Unlock()
 
 	if _, ok := a.levels[name]; !ok {
-		a.levels[name] = new(slog.LevelVar)
-		a.levels[name].Set(a.defaultHandlerOpts.Level.Level())
+		a.levels[name] = a.newLevelLocked(name)
 	}
 
 	return a.levels[name]
@@ -119,6 +150,12 @@ func slogParseLevel(v any) (slog.Level, bool) {
 			return slog.LevelWarn, true
 		case "error", "erro", "err", "e":
 			return slog.LevelError, true
+		default:
+			// allow the output of [slog.Level.String], e.g. `ERROR+2`.
+			var out slog.Level
+			if err := out.UnmarshalText([]byte(lvl)); err == nil {
+				return out, true
+			}
 		}
 	}
 
@@ -136,6 +173,11 @@ func (a *SlogManager) doesKeyMatch(key, check string) bool {
 		return false
 	}
 
+	return doesKeyMatchWildcard(key, check)
+}
+
+// doesKeyMatchWildcard tests if a key matches a wildcard check, it does not test for an exact match.
+func doesKeyMatchWildcard(key, check string) bool {
 	// if is is a single '*' then it's a wildcard and true.
 	if len(check) == 1 && check == "*" {
 		return true
@@ -160,6 +202,18 @@ func (a *SlogManager) doesKeyMatch(key, check string) bool {
 
 // SetLevel attempts to set the level supplied, it will attempt to typecast the value
 // against string, [slog.Level] and [slog.Leveler].
+//
+// Matching loggers are marked as explicitly set, and descendants that inherit their level
+// are updated. If name is not a wildcard and does not exist it is created (e.g. `Server` before
+// any `Server.*` logger exists), so it is used when it is created by Named and current and future
+// descendants inherit it.
+//
+// If name is a wildcard it is also stored as a level rule (see [SlogManager.AddLevelRule]) so
+// loggers created later are set, the return value only reports if an existing logger matched (or
+// is a descendant of name).
+//
+// A name of `*` sets the default level (see [SlogManager.SetDefaultLevel]) and existing loggers go
+// back to inheriting it (the same as [SlogManager.UnsetLevel]), they are not marked as explicitly set.
 func (a *SlogManager) SetLevel(name string, lvl any) bool {
 	a.iLogger.Debug("SetLevel", slog.String("name", name))
 
@@ -168,22 +222,63 @@ func (a *SlogManager) SetLevel(name string, lvl any) bool {
 	a.lock.Lock()
 	defer a.lock.Unlock()
 
-	for itemKey, val := range a.levels {
-		if a.doesKeyMatch(itemKey, name) {
-			if level, ok := slogParseLevel(lvl); ok {
-				a.iLogger.Debug(
-					"setting level for name",
-					slog.String("name", name),
-					slog.String("match", itemKey),
-					slog.String("level", level.String()),
-				)
-				val.Set(level)
+	level, ok := slogParseLevel(lvl)
+	if !ok {
+		return false
+	}
 
+	if name == "*" {
+		a.iLogger.Debug("setting default level", slog.String("level", level.String()))
+
+		for itemKey, val := range a.levels {
+			if itemKey != a.iLoggerName {
+				val.explicit.Store(false)
 				found = true
 			}
 		}
+
+		a.setDefaultLevelLocked(level)
+
+		return found
+	}
+
+	for itemKey, val := range a.levels {
+		if a.doesKeyMatch(itemKey, name) {
+			a.iLogger.Debug(
+				"setting level for name",
+				slog.String("name", name),
+				slog.String("match", itemKey),
+				slog.String("level", level.String()),
+			)
+			val.Set(level)
+			val.explicit.Store(true)
+
+			found = true
+		}
 	}
 
+	if !found && !strings.Contains(name, "*") {
+		a.iLogger.Debug(
+			"creating entry for name",
+			slog.String("name", name),
+			slog.String("level", level.String()),
+		)
+		a.levels[name] = newManagedLevel(level, true)
+
+		found = a.hasDescendantLocked(name)
+	}
+
+	if strings.Contains(name, "*") {
+		a.iLogger.Debug(
+			"storing level rule for name",
+			slog.String("name", name),
+			slog.String("level", level.String()),
+		)
+		a.addLevelRuleLocked(name, level)
+	}
+
+	a.resolveInheritedLocked()
+
 	return found
 }
 
@@ -204,6 +299,8 @@ func (a *SlogManager) String() string {
 }
 
 // Iterator runs a callback function over the levels map item by item.
+//
+// The [slog.Leveler] passed to the callback also implements [InheritedLeveler].
 func (a *SlogManager) Iterator(f func(string, slog.Leveler) error) error {
 	a.lock.RLock()
 	defer a.lock.RUnlock()
@@ -232,6 +329,21 @@ func (a *SlogManager) Delete(name string) {
 	defer a.lock.Unlock()
 
 	delete(a.levels, name)
+
+	a.resolveInheritedLocked()
+}
+
+// withLoggerName adds the logger name to handler if [WithLoggerName] is used.
+func (a *SlogManager) withLoggerName(name string, handler slog.Handler) slog.Handler {
+	if a.loggerNameKey == "" {
+		return handler
+	}
+
+	if nh, ok := handler.(NamedHandler); ok {
+		return nh.WithName(name)
+	}
+
+	return handler.WithAttrs([]slog.Attr{slog.String(a.loggerNameKey, name)})
 }
 
 // Named returns a named [slog.Logger] if any additional parameters are specified it will
@@ -254,7 +366,26 @@ func (a *SlogManager) Named(name string, opts ...any) *slog.Logger {
 		}
 	}
 
-	namedLogger := a.coreNewHandler(name, a.defaultWriter, handlerOpts)
+	var namedLogger slog.Handler
+
+	route := a.route(name)
+	if route.Pattern == "" {
+		namedLogger = a.fanoutHandler(name, handlerOpts)
+	}
+
+	if namedLogger == nil {
+		namedLogger = a.withLoggerName(name, route.Handler(name, route.Writer, handlerOpts))
+	}
+
+	if a.traceContext {
+		namedLogger = NewTraceHandler(namedLogger)
+	}
+
+	namedLogger = a.samplingHandler(name, namedLogger)
+
+	if a.async != nil {
+		namedLogger = &AsyncHandler{handler: namedLogger, state: a.async}
+	}
 
 	return slog.New(namedLogger)
 }
@@ -0,0 +1,178 @@
+package slogtool
+
+import (
+	"log/slog"
+	"strings"
+	"sync/atomic"
+)
+
+// loggerNameSeparator separates the components of a hierarchical logger name, e.g. `Server.Process`.
+const loggerNameSeparator = "."
+
+// InheritedLeveler is implemented by the [slog.Leveler] values returned from [SlogManager.NewLevel]
+// and passed to [SlogManager.Iterator].
+//
+// Inherited reports true when the level has not been set explicitly and is instead inherited from
+// the nearest explicitly set ancestor (or the default level when there is none).
+type InheritedLeveler interface {
+	slog.Leveler
+	Inherited() bool
+}
+
+// managedLevel is a [slog.LevelVar] that tracks if the level was set explicitly or is inherited.
+type managedLevel struct {
+	slog.LevelVar
+
+	explicit atomic.Bool
+}
+
+// newManagedLevel returns a new managedLevel set to lvl.
+func newManagedLevel(lvl slog.Level, explicit bool) *managedLevel {
+	out := new(managedLevel)
+	out.Set(lvl)
+	out.explicit.Store(explicit)
+
+	return out
+}
+
+// Inherited returns true if the level is inherited from an ancestor or the default level.
+func (l *managedLevel) Inherited() bool {
+	return !l.explicit.Load()
+}
+
+// parentLoggerName returns the name of the parent logger, or an empty string if there is no parent.
+func parentLoggerName(name string) string {
+	idx := strings.LastIndex(name, loggerNameSeparator)
+	if idx <= 0 {
+		return ""
+	}
+
+	return name[:idx]
+}
+
+// isAncestorLoggerName returns true if ancestor is a parent (or grandparent, etc) of name.
+func isAncestorLoggerName(ancestor, name string) bool {
+	return strings.HasPrefix(name, ancestor+loggerNameSeparator)
+}
+
+// inheritedLevelLocked returns the level of the nearest explicitly set ancestor of name,
+// or the default level if there is none.
+//
+// The caller must hold the lock.
+func (a *SlogManager) inheritedLevelLocked(name string) slog.Level {
+	if parent, ok := a.explicitAncestorLocked(name); ok {
+		return a.levels[parent].Level()
+	}
+
+	return a.defaultHandlerOpts.Level.Level()
+}
+
+// explicitAncestorLocked returns the name of the nearest explicitly set ancestor of name.
+//
+// The caller must hold the lock.
+func (a *SlogManager) explicitAncestorLocked(name string) (string, bool) {
+	for parent := parentLoggerName(name); parent != ""; parent = parentLoggerName(parent) {
+		if lvl, ok := a.levels[parent]; ok && !lvl.Inherited() {
+			return parent, true
+		}
+	}
+
+	return "", false
+}
+
+// DefaultLevel returns the default level, inherited by loggers without an explicitly set ancestor.
+func (a *SlogManager) DefaultLevel() slog.Level {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	return a.defaultHandlerOpts.Level.Level()
+}
+
+// SetDefaultLevel sets the default level (the same as a `*` entry in a level spec), loggers that inherit
+// the default level are updated, explicitly set loggers are not changed.
+func (a *SlogManager) SetDefaultLevel(lvl any) bool {
+	level, ok := slogParseLevel(lvl)
+	if !ok {
+		return false
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.setDefaultLevelLocked(level)
+
+	return true
+}
+
+// setDefaultLevelLocked sets the default level and updates every level that inherits it.
+//
+// The caller must hold the lock.
+func (a *SlogManager) setDefaultLevelLocked(lvl slog.Level) {
+	a.defaultHandlerOpts.Level = lvl
+	a.resolveInheritedLocked()
+}
+
+// resolveInheritedLocked updates every inherited level to match its nearest explicitly set ancestor.
+//
+// The caller must hold the lock.
+func (a *SlogManager) resolveInheritedLocked() {
+	for name, lvl := range a.levels {
+		if lvl.Inherited() {
+			lvl.Set(a.inheritedLevelLocked(name))
+		}
+	}
+}
+
+// hasDescendantLocked returns true if there is a stored logger that is a descendant of name.
+//
+// The caller must hold the lock.
+func (a *SlogManager) hasDescendantLocked(name string) bool {
+	for itemKey := range a.levels {
+		if isAncestorLoggerName(name, itemKey) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsInherited returns true if the named logger exists and its level is inherited from an
+// ancestor (or the default level) rather than set explicitly.
+func (a *SlogManager) IsInherited(name string) bool {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	lvl, ok := a.levels[name]
+
+	return ok && lvl.Inherited()
+}
+
+// UnsetLevel removes the explicit level from any logger that matches name (using the same
+// matching as SetLevel), the matching loggers will then inherit the level of their nearest
+// explicitly set ancestor (or the default level). Any level rule with the same pattern is removed.
+func (a *SlogManager) UnsetLevel(name string) bool {
+	a.iLogger.Debug("UnsetLevel", slog.String("name", name))
+
+	found := false
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	for itemKey, val := range a.levels {
+		if itemKey == a.iLoggerName || !a.doesKeyMatch(itemKey, name) {
+			continue
+		}
+
+		val.explicit.Store(false)
+
+		found = true
+	}
+
+	if a.removeLevelRuleLocked(name) {
+		found = true
+	}
+
+	a.resolveInheritedLocked()
+
+	return found
+}
@@ -0,0 +1,235 @@
+package slogtool_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/na4ma4/go-slogtool"
+)
+
+func TestSlogManagerHierarchyInheritsFromParent(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	ctx := context.Background()
+	testLog, _ := slogtool.NewSlogManager(
+		ctx,
+		slogtool.WithWriter(buf),
+		slogtool.WithDefaultLevel(slog.LevelInfo),
+	)
+
+	_ = testLog.Named("Server")
+	existing := testLog.Named("Server.Process")
+
+	if ok := testLog.SetLevel("Server", "debug"); !ok {
+		t.Fatal("expected SetLevel(Server) to return true")
+	}
+
+	future := testLog.Named("Server.Process.Worker")
+	other := testLog.Named("Client")
+
+	existing.DebugContext(ctx, "existing:debug")
+	future.DebugContext(ctx, "future:debug")
+	other.DebugContext(ctx, "other:debug")
+
+	expectLogLines(t, buf, []string{
+		"time=" + timeTestString + " level=DEBUG msg=existing:debug",
+		"time=" + timeTestString + " level=DEBUG msg=future:debug",
+	})
+
+	expect := "Client:INFO,Internal.SlogManager:ERROR+247,Server.Process.Worker:DEBUG,Server.Process:DEBUG,Server:DEBUG"
+	if diff := cmp.Diff(testLog.String(), expect); diff != "" {
+		t.Errorf("SlogManager: string : -got +want:\n%s", diff)
+	}
+}
+
+func TestSlogManagerHierarchyExplicitChildWins(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	ctx := context.Background()
+	testLog, _ := slogtool.NewSlogManager(
+		ctx,
+		slogtool.WithWriter(buf),
+		slogtool.WithDefaultLevel(slog.LevelInfo),
+	)
+
+	_ = testLog.Named("Server")
+	child := testLog.Named("Server.Process", slog.LevelWarn)
+	grandchild := testLog.Named("Server.Process.Worker")
+
+	testLog.SetLevel("Server", "debug")
+
+	child.InfoContext(ctx, "child:info")
+	grandchild.InfoContext(ctx, "grandchild:info")
+
+	expectLogLines(t, buf, []string{})
+
+	if testLog.IsInherited("Server.Process") {
+		t.Error("expected Server.Process to be explicit")
+	}
+	if !testLog.IsInherited("Server.Process.Worker") {
+		t.Error("expected Server.Process.Worker to be inherited")
+	}
+
+	if ok := testLog.UnsetLevel("Server.Process"); !ok {
+		t.Fatal("expected UnsetLevel(Server.Process) to return true")
+	}
+
+	child.DebugContext(ctx, "child:debug")
+	grandchild.DebugContext(ctx, "grandchild:debug")
+
+	expectLogLines(t, buf, []string{
+		"time=" + timeTestString + " level=DEBUG msg=child:debug",
+		"time=" + timeTestString + " level=DEBUG msg=grandchild:debug",
+	})
+}
+
+func TestSlogManagerHierarchyCreatesAncestor(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	ctx := context.Background()
+	testLog, _ := slogtool.NewSlogManager(
+		ctx,
+		slogtool.WithWriter(buf),
+		slogtool.WithDefaultLevel(slog.LevelInfo),
+	)
+
+	existing := testLog.Named("Server.Process")
+
+	if ok := testLog.SetLevel("Server", "debug"); !ok {
+		t.Fatal("expected SetLevel(Server) to return true when descendants exist")
+	}
+	if !testLog.IsLogger("Server") || testLog.IsInherited("Server") {
+		t.Fatal("expected Server to be created with an explicit level")
+	}
+
+	future := testLog.Named("Server.Other")
+
+	existing.DebugContext(ctx, "existing:debug")
+	future.DebugContext(ctx, "future:debug")
+
+	expectLogLines(t, buf, []string{
+		"time=" + timeTestString + " level=DEBUG msg=existing:debug",
+		"time=" + timeTestString + " level=DEBUG msg=future:debug",
+	})
+
+	testLog.Delete("Server")
+
+	existing.DebugContext(ctx, "existing:debug2")
+	expectLogLines(t, buf, []string{})
+}
+
+func TestSlogManagerHierarchySetLevelBeforeNamed(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	ctx := context.Background()
+	testLog, _ := slogtool.NewSlogManager(
+		ctx,
+		slogtool.WithWriter(buf),
+		slogtool.WithDefaultLevel(slog.LevelInfo),
+	)
+
+	if ok := testLog.SetLevel("Server", "debug"); ok {
+		t.Error("expected SetLevel(Server) to return false when no logger matches")
+	}
+	if !testLog.IsLogger("Server") || testLog.IsInherited("Server") {
+		t.Fatal("expected Server to be created with an explicit level")
+	}
+
+	server := testLog.Named("Server")
+	process := testLog.Named("Server.Process")
+	worker := testLog.Named("Server.Process.Worker")
+
+	server.DebugContext(ctx, "server:debug")
+	process.DebugContext(ctx, "process:debug")
+	worker.DebugContext(ctx, "worker:debug")
+
+	expectLogLines(t, buf, []string{
+		"time=" + timeTestString + " level=DEBUG msg=server:debug",
+		"time=" + timeTestString + " level=DEBUG msg=process:debug",
+		"time=" + timeTestString + " level=DEBUG msg=worker:debug",
+	})
+
+	if !testLog.IsInherited("Server.Process") {
+		t.Error("expected Server.Process to inherit from Server")
+	}
+}
+
+func TestSlogManagerHierarchySetDefaultWildcard(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	ctx := context.Background()
+	testLog, _ := slogtool.NewSlogManager(
+		ctx,
+		slogtool.WithWriter(buf),
+		slogtool.WithDefaultLevel(slog.LevelInfo),
+	)
+
+	process := testLog.Named("Server.Process")
+
+	if ok := testLog.SetLevel("*", "warn"); !ok {
+		t.Error("expected SetLevel(*) to return true")
+	}
+	if !testLog.IsInherited("Server.Process") {
+		t.Fatal("expected Server.Process to still inherit after SetLevel(*)")
+	}
+	if lvl := testLog.DefaultLevel(); lvl != slog.LevelWarn {
+		t.Errorf("default level mismatch: got=%s want=%s", lvl, slog.LevelWarn)
+	}
+
+	process.InfoContext(ctx, "process:info")
+
+	testLog.SetLevel("Server", "debug")
+
+	process.DebugContext(ctx, "process:debug")
+
+	expectLogLines(t, buf, []string{
+		"time=" + timeTestString + " level=DEBUG msg=process:debug",
+	})
+
+	if !testLog.IsInherited("Server.Process") {
+		t.Error("expected Server.Process to inherit from Server")
+	}
+}
+
+func TestSlogManagerHierarchyIteratorInherited(t *testing.T) {
+	t.Parallel()
+
+	testLog, _ := slogtool.NewSlogManager(
+		context.Background(),
+		slogtool.WithWriter(bytes.NewBuffer(nil)),
+	)
+
+	_ = testLog.Named("Server", slog.LevelDebug)
+	_ = testLog.Named("Server.Process")
+
+	got := map[string]bool{}
+	err := testLog.Iterator(func(name string, lvl slog.Leveler) error {
+		il, ok := lvl.(slogtool.InheritedLeveler)
+		if !ok {
+			t.Fatalf("expected %s leveler to implement InheritedLeveler, got=%T", name, lvl)
+		}
+		got[name] = il.Inherited()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Iterator returned error: %v", err)
+	}
+
+	expect := map[string]bool{
+		"Internal.SlogManager": false,
+		"Server":               false,
+		"Server.Process":       true,
+	}
+	if diff := cmp.Diff(got, expect); diff != "" {
+		t.Errorf("SlogManager: inherited : -got +want:\n%s", diff)
+	}
+}
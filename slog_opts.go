@@ -1,9 +1,9 @@
 package slogtool
 
 import (
+	"context"
 	"fmt"
 	"io"
-	"log/slog"
 )
 
 // WithWriter is a SlogManagerOpts that sets the default writer for all loggers created by the SlogManager.
@@ -32,9 +32,10 @@ func WithDefaultLevel(lvl any) SlogManagerOpts {
 func WithInternalLevel(lvl any) SlogManagerOpts {
 	return func(sm *SlogManager) error {
 		l := sm.NewLevel(sm.iLoggerName)
-		if v, ok := l.(*slog.LevelVar); ok {
+		if v, ok := l.(*managedLevel); ok {
 			if lv, lok := slogParseLevel(lvl); lok {
 				v.Set(lv)
+				v.explicit.Store(true)
 			}
 		}
 		return nil
@@ -62,9 +63,7 @@ func WithCustomHandler(custom CustomNewHandler) SlogManagerOpts {
 // WithTextHandler is a SlogManagerOpts that sets the handler for all loggers created by the SlogManager to a TextHandler.
 func WithTextHandler() SlogManagerOpts {
 	return func(sm *SlogManager) error {
-		sm.coreNewHandler = func(_ string, w io.Writer, opts *slog.HandlerOptions) slog.Handler {
-			return slog.NewTextHandler(w, opts)
-		}
+		sm.coreNewHandler = TextHandler
 		return nil
 	}
 }
@@ -72,9 +71,87 @@ func WithTextHandler() SlogManagerOpts {
 // WithJSONHandler is a SlogManagerOpts that sets the handler for all loggers created by the SlogManager to a JSONHandler.
 func WithJSONHandler() SlogManagerOpts {
 	return func(sm *SlogManager) error {
-		sm.coreNewHandler = func(_ string, w io.Writer, opts *slog.HandlerOptions) slog.Handler {
-			return slog.NewJSONHandler(w, opts)
+		sm.coreNewHandler = JSONHandler
+		return nil
+	}
+}
+
+// WithLoggerName is a SlogManagerOpts that adds the name of the logger to every record as an attribute
+// with the key (or [DefaultLoggerNameKey] if key is empty), e.g. `logger=Server.Process`.
+//
+// Handlers that implement [NamedHandler] (such as prettylog.Handler) render the name themselves.
+func WithLoggerName(key string) SlogManagerOpts {
+	return func(sm *SlogManager) error {
+		if key == "" {
+			key = DefaultLoggerNameKey
 		}
+		sm.loggerNameKey = key
+		return nil
+	}
+}
+
+// WithRoute is a SlogManagerOpts that routes loggers created by the SlogManager with a name that matches
+// pattern (exact or wildcard, the same as SetLevel) to the writer and handler, a nil writer or handler
+// uses the default.
+//
+// When more than one route matches a name the most specific pattern wins (see [SlogManager.AddLevelRule]).
+func WithRoute(pattern string, w io.Writer, handler CustomNewHandler) SlogManagerOpts {
+	return func(sm *SlogManager) error {
+		sm.routes = append(sm.routes, LoggerRoute{
+			Pattern: pattern,
+			Writer:  w,
+			Handler: handler,
+		})
+		return nil
+	}
+}
+
+// WithSampling is a SlogManagerOpts that wraps loggers created by the SlogManager with a name that matches
+// pattern (exact or wildcard, the same as SetLevel) in a [SamplingHandler], each logger name has its own
+// sampling and rate limiting state, shared by every logger created with that name.
+//
+// If SummaryInterval is set the summary is emitted every interval from a background goroutine,
+// [SlogManager.Close] should be called to stop it.
+//
+// When more than one pattern matches a name the most specific pattern wins (see [SlogManager.AddLevelRule]).
+func WithSampling(pattern string, cfg SamplingConfig) SlogManagerOpts {
+	return func(sm *SlogManager) error {
+		sm.sampling = append(sm.sampling, samplingRoute{
+			pattern: pattern,
+			cfg:     cfg,
+		})
+		return nil
+	}
+}
+
+// WithAsync is a SlogManagerOpts that wraps every logger created by the SlogManager in an [AsyncHandler],
+// the loggers share a single queue, [SlogManager.Close] should be called to flush and stop it.
+func WithAsync(cfg AsyncConfig) SlogManagerOpts {
+	return func(sm *SlogManager) error {
+		if sm.async != nil {
+			_ = sm.async.close(context.Background())
+		}
+		sm.async = newAsyncState(cfg)
+		return nil
+	}
+}
+
+// WithFanout is a SlogManagerOpts that passes the records of every logger created by the SlogManager
+// to each of the sinks using a [MultiHandler], a nil writer or handler in a sink uses the default.
+//
+// The sinks replace the default route, loggers that match a route added with [WithRoute] only use that route.
+func WithFanout(sinks ...FanoutSink) SlogManagerOpts {
+	return func(sm *SlogManager) error {
+		sm.sinks = append(sm.sinks, sinks...)
+		return nil
+	}
+}
+
+// WithTraceContext is a SlogManagerOpts that wraps every logger created by the SlogManager in a [TraceHandler],
+// adding the trace context stored in the context to records logged with the `*Context` methods.
+func WithTraceContext() SlogManagerOpts {
+	return func(sm *SlogManager) error {
+		sm.traceContext = true
 		return nil
 	}
 }
@@ -0,0 +1,65 @@
+package slogtool
+
+import (
+	"io"
+)
+
+// LoggerRoute defines the writer and handler used for loggers created by the SlogManager
+// with a name that matches Pattern, an empty Pattern is the default route.
+type LoggerRoute struct {
+	Pattern string
+	Writer  io.Writer
+	Handler CustomNewHandler
+}
+
+// route returns the route for name, with the default writer and handler filled in.
+func (a *SlogManager) route(name string) LoggerRoute {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	out := LoggerRoute{
+		Writer:  a.defaultWriter,
+		Handler: a.coreNewHandler,
+	}
+
+	idx := a.bestPatternMatch(name, len(a.routes), func(i int) string {
+		return a.routes[i].Pattern
+	})
+
+	if idx < 0 {
+		return out
+	}
+
+	best := a.routes[idx]
+
+	out.Pattern = best.Pattern
+
+	if best.Writer != nil {
+		out.Writer = best.Writer
+	}
+
+	if best.Handler != nil {
+		out.Handler = best.Handler
+	}
+
+	return out
+}
+
+// RouteIterator runs a callback function over the stored loggers with the route used for each,
+// the default route has an empty Pattern.
+func (a *SlogManager) RouteIterator(f func(name string, route LoggerRoute) error) error {
+	a.lock.RLock()
+	names := make([]string, 0, len(a.levels))
+	for k := range a.levels {
+		names = append(names, k)
+	}
+	a.lock.RUnlock()
+
+	for _, name := range names {
+		if err := f(name, a.route(name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
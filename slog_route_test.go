@@ -0,0 +1,74 @@
+package slogtool_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/na4ma4/go-slogtool"
+)
+
+func TestSlogManagerWithRoute(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	auditBuf := bytes.NewBuffer(nil)
+	accessBuf := bytes.NewBuffer(nil)
+	ctx := context.Background()
+	testLog, err := slogtool.NewSlogManager(
+		ctx,
+		slogtool.WithWriter(buf),
+		slogtool.WithDefaultLevel(slog.LevelDebug),
+		slogtool.WithRoute("Audit.*", auditBuf, slogtool.JSONHandler),
+		slogtool.WithRoute("HTTP.Access", accessBuf, nil),
+		slogtool.WithRoute("Audit.Local", nil, slogtool.TextHandler),
+	)
+	if err != nil {
+		t.Fatalf("NewSlogManager returned error: %v", err)
+	}
+
+	testLog.Named("Audit.Login").InfoContext(ctx, "audit:login")
+	testLog.Named("Audit.Local").InfoContext(ctx, "audit:local")
+	testLog.Named("HTTP.Access").InfoContext(ctx, "http:access")
+	testLog.Named("Server").InfoContext(ctx, "server:info")
+
+	expectLogLines(t, auditBuf, []string{
+		`{"time":"` + timeTestString + `","level":"INFO","msg":"audit:login"}`,
+	})
+	expectLogLines(t, accessBuf, []string{
+		"time=" + timeTestString + " level=INFO msg=http:access",
+	})
+	expectLogLines(t, buf, []string{
+		"time=" + timeTestString + " level=INFO msg=audit:local",
+		"time=" + timeTestString + " level=INFO msg=server:info",
+	})
+
+	routes := []string{}
+	err = testLog.RouteIterator(func(name string, route slogtool.LoggerRoute) error {
+		if route.Writer == nil || route.Handler == nil {
+			t.Errorf("expected route for %s to have a writer and handler", name)
+		}
+		routes = append(routes, name+":"+route.Pattern)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RouteIterator returned error: %v", err)
+	}
+
+	sort.Strings(routes)
+
+	expect := []string{
+		"Audit.Local:Audit.Local",
+		"Audit.Login:Audit.*",
+		"HTTP.Access:HTTP.Access",
+		"Internal.SlogManager:",
+		"Server:",
+	}
+	if diff := cmp.Diff(routes, expect); diff != "" {
+		t.Errorf("SlogManager: routes : -got +want:\n%s", diff)
+	}
+}
@@ -0,0 +1,128 @@
+package slogtool
+
+import (
+	"log/slog"
+	"math"
+	"strings"
+)
+
+// patternSpecificity returns how specific a name pattern is, an exact name is the most
+// specific, otherwise the more non-wildcard characters the more specific.
+func patternSpecificity(pattern string) int {
+	if !strings.Contains(pattern, "*") {
+		return math.MaxInt
+	}
+
+	return len(pattern) - strings.Count(pattern, "*")
+}
+
+// bestPatternMatch returns the index of the most specific of the n patterns that matches name
+// (the latest pattern wins a tie), or -1 if none match.
+func (a *SlogManager) bestPatternMatch(name string, n int, pattern func(i int) string) int {
+	best, specificity := -1, 0
+
+	for i := range n {
+		p := pattern(i)
+		if !a.doesKeyMatch(name, p) {
+			continue
+		}
+
+		if v := patternSpecificity(p); best < 0 || v >= specificity {
+			best, specificity = i, v
+		}
+	}
+
+	return best
+}
+
+// addLevelRuleLocked stores a level rule, replacing any existing rule with the same pattern,
+// the new rule is treated as the latest rule, a pattern of `*` sets the default level instead.
+//
+// The caller must hold the lock.
+func (a *SlogManager) addLevelRuleLocked(pattern string, lvl slog.Level) {
+	if pattern == "*" {
+		a.setDefaultLevelLocked(lvl)
+		return
+	}
+
+	a.removeLevelRuleLocked(pattern)
+	a.levelRules = append(a.levelRules, LevelSpecEntry{Name: pattern, Level: lvl})
+}
+
+// removeLevelRuleLocked removes the level rule with the pattern, returning true if it existed.
+//
+// The caller must hold the lock.
+func (a *SlogManager) removeLevelRuleLocked(pattern string) bool {
+	for i, rule := range a.levelRules {
+		if strings.EqualFold(rule.Name, pattern) {
+			a.levelRules = append(a.levelRules[:i], a.levelRules[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// newLevelLocked returns a new level for name, set by the most specific matching level rule
+// (the latest rule wins a tie), or inherited if no rule matches.
+//
+// A rule is only used if it is at least as specific as the nearest explicitly set ancestor, which
+// is as specific as the pattern of its descendants (e.g. `Server` is as specific as `Server.*`).
+//
+// The caller must hold the lock.
+func (a *SlogManager) newLevelLocked(name string) *managedLevel {
+	idx := a.bestPatternMatch(name, len(a.levelRules), func(i int) string {
+		return a.levelRules[i].Name
+	})
+
+	if idx >= 0 {
+		parent, ok := a.explicitAncestorLocked(name)
+		if !ok || patternSpecificity(a.levelRules[idx].Name) >= len(parent)+len(loggerNameSeparator) {
+			return newManagedLevel(a.levelRules[idx].Level, true)
+		}
+	}
+
+	return newManagedLevel(a.inheritedLevelLocked(name), false)
+}
+
+// AddLevelRule stores a level rule that is applied to loggers created later that match pattern
+// (exact or wildcard, the same as SetLevel), it does not change the level of existing loggers.
+//
+// A pattern of `*` is not stored, it sets the default level (see [SlogManager.DefaultLevel]), which is
+// inherited by existing and future loggers without an explicitly set ancestor.
+//
+// When more than one rule matches a new logger the most specific pattern wins (an exact name, then
+// the pattern with the most non-wildcard characters), the latest rule wins a tie.
+func (a *SlogManager) AddLevelRule(pattern string, lvl any) bool {
+	level, ok := slogParseLevel(lvl)
+	if !ok {
+		return false
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.addLevelRuleLocked(pattern, level)
+
+	return true
+}
+
+// RemoveLevelRule removes the level rule with the pattern, returning true if it existed,
+// it does not change the level of existing loggers.
+func (a *SlogManager) RemoveLevelRule(pattern string) bool {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	return a.removeLevelRuleLocked(pattern)
+}
+
+// LevelRules returns the stored level rules, oldest first.
+func (a *SlogManager) LevelRules() LevelSpec {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	out := make(LevelSpec, len(a.levelRules))
+	copy(out, a.levelRules)
+
+	return out
+}
@@ -0,0 +1,157 @@
+package slogtool_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/na4ma4/go-slogtool"
+)
+
+func TestSlogManagerSetLevelPendingRule(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	ctx := context.Background()
+	testLog, _ := slogtool.NewSlogManager(
+		ctx,
+		slogtool.WithWriter(buf),
+		slogtool.WithDefaultLevel(slog.LevelInfo),
+	)
+
+	if ok := testLog.SetLevel("Worker.*", "debug"); ok {
+		t.Fatal("expected SetLevel to return false when no logger matches")
+	}
+
+	worker := testLog.Named("Worker.One")
+	other := testLog.Named("Other")
+
+	worker.DebugContext(ctx, "worker:debug")
+	other.DebugContext(ctx, "other:debug")
+
+	expectLogLines(t, buf, []string{
+		"time=" + timeTestString + " level=DEBUG msg=worker:debug",
+	})
+
+	if testLog.IsInherited("Worker.One") {
+		t.Error("expected Worker.One to be explicitly set by the rule")
+	}
+}
+
+func TestSlogManagerLevelRulePrecedence(t *testing.T) {
+	t.Parallel()
+
+	testLog, _ := slogtool.NewSlogManager(
+		context.Background(),
+		slogtool.WithWriter(bytes.NewBuffer(nil)),
+		slogtool.WithDefaultLevel(slog.LevelInfo),
+	)
+
+	testLog.SetLevel("Worker.*", "debug")
+	testLog.SetLevel("*", "error")
+	testLog.SetLevel("Worker.Pool.*", "warn")
+	testLog.SetLevel("*.Pool.*", "info")
+	testLog.SetLevel("Worker.Pool.Exact", "debug")
+
+	_ = testLog.Named("Worker.One")
+	_ = testLog.Named("Worker.Pool.One")
+	_ = testLog.Named("Worker.Pool.Exact")
+	_ = testLog.Named("Client")
+
+	expect := "Client:ERROR,Internal.SlogManager:ERROR+247," +
+		"Worker.One:DEBUG,Worker.Pool.Exact:DEBUG,Worker.Pool.One:WARN"
+	if diff := cmp.Diff(testLog.String(), expect); diff != "" {
+		t.Errorf("SlogManager: string : -got +want:\n%s", diff)
+	}
+
+	// same specificity, latest rule wins.
+	testLog.SetLevel("*b.Tie", "error")
+	testLog.SetLevel("Job.T*", "debug")
+
+	if lvl := testLog.NewLevel("Job.Tie").Level(); lvl != slog.LevelDebug {
+		t.Errorf("expected latest rule to win a tie, got=%s", lvl)
+	}
+
+	testLog.Delete("Job.Tie")
+	testLog.SetLevel("*b.Tie", "warn")
+
+	if lvl := testLog.NewLevel("Job.Tie").Level(); lvl != slog.LevelWarn {
+		t.Errorf("expected replaced rule to become the latest rule, got=%s", lvl)
+	}
+}
+
+func TestSlogManagerLevelRulesListAndRemove(t *testing.T) {
+	t.Parallel()
+
+	testLog, _ := slogtool.NewSlogManager(
+		context.Background(),
+		slogtool.WithWriter(bytes.NewBuffer(nil)),
+		slogtool.WithLevelSpec("Server.*=debug"),
+	)
+
+	testLog.SetLevel("Worker.*", "warn")
+	testLog.AddLevelRule("Client", "error")
+	testLog.SetLevel("Server.*", "info")
+
+	if diff := cmp.Diff(testLog.LevelRules().String(), "Worker.*=WARN,Client=ERROR,Server.*=INFO"); diff != "" {
+		t.Errorf("SlogManager: rules : -got +want:\n%s", diff)
+	}
+
+	if ok := testLog.RemoveLevelRule("Worker.*"); !ok {
+		t.Error("expected RemoveLevelRule(Worker.*) to return true")
+	}
+	if ok := testLog.RemoveLevelRule("Worker.*"); ok {
+		t.Error("expected second RemoveLevelRule(Worker.*) to return false")
+	}
+	if ok := testLog.UnsetLevel("Client"); !ok {
+		t.Error("expected UnsetLevel(Client) to remove the rule")
+	}
+	if ok := testLog.AddLevelRule("Invalid", true); ok {
+		t.Error("expected AddLevelRule to return false for an invalid level")
+	}
+
+	if diff := cmp.Diff(testLog.LevelRules().String(), "Server.*=INFO"); diff != "" {
+		t.Errorf("SlogManager: rules : -got +want:\n%s", diff)
+	}
+
+	if lvl := testLog.NewLevel("Worker.One").Level(); lvl != slog.LevelInfo {
+		t.Errorf("expected removed rule not to apply, got=%s", lvl)
+	}
+}
+
+func TestSlogManagerLevelRuleExplicitAncestor(t *testing.T) {
+	t.Parallel()
+
+	testLog, _ := slogtool.NewSlogManager(
+		context.Background(),
+		slogtool.WithWriter(bytes.NewBuffer(nil)),
+		slogtool.WithDefaultLevel(slog.LevelInfo),
+	)
+
+	testLog.SetLevel("*", "warn")
+	testLog.SetLevel("Server", "debug")
+
+	if lvl := testLog.NewLevel("Server.X").Level(); lvl != slog.LevelDebug {
+		t.Errorf("expected Server.X to inherit from Server, got=%s", lvl)
+	}
+	if lvl := testLog.NewLevel("Client").Level(); lvl != slog.LevelWarn {
+		t.Errorf("expected Client to use the default level, got=%s", lvl)
+	}
+	if rules := testLog.LevelRules(); len(rules) != 0 {
+		t.Errorf("expected `*` to set the default level instead of a rule, got=%s", rules)
+	}
+
+	// a less specific rule does not beat an explicitly set ancestor, a more specific one does.
+	testLog.AddLevelRule("S*", "error")
+	testLog.AddLevelRule("Server.Pool.*", "warn")
+
+	if lvl := testLog.NewLevel("Server.Y").Level(); lvl != slog.LevelDebug {
+		t.Errorf("expected Server.Y to inherit from Server, got=%s", lvl)
+	}
+	if lvl := testLog.NewLevel("Server.Pool.One").Level(); lvl != slog.LevelWarn {
+		t.Errorf("expected Server.Pool.One to be set by the rule, got=%s", lvl)
+	}
+}
@@ -0,0 +1,186 @@
+package slogtool
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+)
+
+// DefaultLevelSpecEnv is the default environment variable read by [WithLevelSpecFromEnv].
+const DefaultLevelSpecEnv = "SLOG_LEVELS"
+
+const (
+	levelSpecSeparator      = ","
+	levelSpecEntrySeparator = "="
+)
+
+// ErrInvalidLevelSpec is returned when a level spec can not be parsed.
+var ErrInvalidLevelSpec = errors.New("invalid level spec")
+
+// LevelSpecEntry is a single `name=level` entry of a [LevelSpec], an empty Name represents
+// the default level.
+type LevelSpecEntry struct {
+	Name  string
+	Level slog.Level
+}
+
+// String returns the entry in level spec format.
+func (e LevelSpecEntry) String() string {
+	if e.Name == "" {
+		return e.Level.String()
+	}
+
+	return e.Name + levelSpecEntrySeparator + e.Level.String()
+}
+
+// LevelSpec is a parsed level spec, e.g. `info,Server.*=debug,Internal.SlogManager=warn`.
+//
+// Entries are applied in order, so later entries override earlier ones, names are matched the
+// same as [SlogManager.SetLevel] (exact or wildcard), an entry without a name (or a name of `*`)
+// sets the default level. Wildcard entries are stored as level rules (see [SlogManager.AddLevelRule]).
+type LevelSpec []LevelSpecEntry
+
+// ParseLevelSpec parses a comma separated list of `name=level` entries (or a bare `level` to
+// set the default level) into a [LevelSpec].
+func ParseLevelSpec(spec string) (LevelSpec, error) {
+	out := LevelSpec{}
+
+	for item := range strings.SplitSeq(spec, levelSpecSeparator) {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, lvl, found := strings.Cut(item, levelSpecEntrySeparator)
+		if !found {
+			name, lvl = "", name
+		}
+
+		name, lvl = strings.TrimSpace(name), strings.TrimSpace(lvl)
+		if found && name == "" {
+			return nil, fmt.Errorf("%w: missing name in entry %q", ErrInvalidLevelSpec, item)
+		}
+
+		level, ok := slogParseLevel(lvl)
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid level in entry %q", ErrInvalidLevelSpec, item)
+		}
+
+		out = append(out, LevelSpecEntry{Name: name, Level: level})
+	}
+
+	return out, nil
+}
+
+// String returns the level spec in the format accepted by [ParseLevelSpec].
+func (s LevelSpec) String() string {
+	out := make([]string, 0, len(s))
+
+	for _, entry := range s {
+		out = append(out, entry.String())
+	}
+
+	return strings.Join(out, levelSpecSeparator)
+}
+
+// WithLevelSpec is a SlogManagerOpts that applies a level spec (see [ParseLevelSpec]), wildcard entries
+// are stored as level rules so they are also applied to loggers created later by Named.
+func WithLevelSpec(spec string) SlogManagerOpts {
+	return func(sm *SlogManager) error {
+		parsed, err := ParseLevelSpec(spec)
+		if err != nil {
+			return err
+		}
+
+		sm.lock.Lock()
+		defer sm.lock.Unlock()
+
+		sm.applyLevelSpecLocked(parsed)
+
+		return nil
+	}
+}
+
+// WithLevelSpecFromEnv is a SlogManagerOpts that applies the level spec stored in the environment
+// variable name (see [WithLevelSpec]), if name is empty [DefaultLevelSpecEnv] is used.
+//
+// If the environment variable is not set or empty, no levels are changed.
+func WithLevelSpecFromEnv(name string) SlogManagerOpts {
+	if name == "" {
+		name = DefaultLevelSpecEnv
+	}
+
+	return func(sm *SlogManager) error {
+		spec := os.Getenv(name)
+		if spec == "" {
+			return nil
+		}
+
+		if err := WithLevelSpec(spec)(sm); err != nil {
+			return fmt.Errorf("unable to apply %s: %w", name, err)
+		}
+
+		return nil
+	}
+}
+
+// applyLevelSpecLocked applies a parsed level spec, entries without a wildcard are created if
+// they do not exist (so descendants inherit them), wildcard entries are stored as level rules.
+//
+// The caller must hold the lock.
+func (a *SlogManager) applyLevelSpecLocked(spec LevelSpec) {
+	for _, entry := range spec {
+		switch {
+		case entry.Name == "" || entry.Name == "*":
+			a.setDefaultLevelLocked(entry.Level)
+		case strings.Contains(entry.Name, "*"):
+			a.addLevelRuleLocked(entry.Name, entry.Level)
+
+			for itemKey, val := range a.levels {
+				if a.doesKeyMatch(itemKey, entry.Name) {
+					val.Set(entry.Level)
+					val.explicit.Store(true)
+				}
+			}
+		default:
+			if val, ok := a.levels[entry.Name]; ok {
+				val.Set(entry.Level)
+				val.explicit.Store(true)
+
+				continue
+			}
+
+			a.levels[entry.Name] = newManagedLevel(entry.Level, true)
+		}
+	}
+
+	a.resolveInheritedLocked()
+}
+
+// LevelSpec returns the current state of the SlogManager in the format accepted by [ParseLevelSpec],
+// the default level, followed by the wildcard rules, followed by the explicitly set loggers sorted by name.
+func (a *SlogManager) LevelSpec() string {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	out := LevelSpec{{Level: a.defaultHandlerOpts.Level.Level()}}
+	out = append(out, a.levelRules...)
+
+	names := make([]string, 0, len(a.levels))
+	for k, v := range a.levels {
+		if !v.Inherited() {
+			names = append(names, k)
+		}
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		out = append(out, LevelSpecEntry{Name: name, Level: a.levels[name].Level()})
+	}
+
+	return out.String()
+}
@@ -0,0 +1,194 @@
+package slogtool_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/na4ma4/go-slogtool"
+)
+
+func TestParseLevelSpec(t *testing.T) {
+	t.Parallel()
+
+	spec, err := slogtool.ParseLevelSpec(" info, Server.*=debug ,,Internal.SlogManager=WARN,Worker=ERROR+2")
+	if err != nil {
+		t.Fatalf("ParseLevelSpec returned error: %v", err)
+	}
+
+	expect := slogtool.LevelSpec{
+		{Name: "", Level: slog.LevelInfo},
+		{Name: "Server.*", Level: slog.LevelDebug},
+		{Name: "Internal.SlogManager", Level: slog.LevelWarn},
+		{Name: "Worker", Level: slog.LevelError + 2},
+	}
+	if diff := cmp.Diff(spec, expect); diff != "" {
+		t.Errorf("ParseLevelSpec: -got +want:\n%s", diff)
+	}
+
+	if diff := cmp.Diff(spec.String(), "INFO,Server.*=DEBUG,Internal.SlogManager=WARN,Worker=ERROR+2"); diff != "" {
+		t.Errorf("LevelSpec.String: -got +want:\n%s", diff)
+	}
+}
+
+func TestParseLevelSpecErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, spec := range []string{"loud", "Server=loud", "=debug", "Server="} {
+		if _, err := slogtool.ParseLevelSpec(spec); !errors.Is(err, slogtool.ErrInvalidLevelSpec) {
+			t.Errorf("ParseLevelSpec(%q): got err=%v, want ErrInvalidLevelSpec", spec, err)
+		}
+	}
+}
+
+func TestSlogManagerWithLevelSpec(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	ctx := context.Background()
+	testLog, err := slogtool.NewSlogManager(
+		ctx,
+		slogtool.WithWriter(buf),
+		slogtool.WithLevelSpec("*=warn,Server.*=debug,Client=info,Internal.SlogManager=error"),
+	)
+	if err != nil {
+		t.Fatalf("NewSlogManager returned error: %v", err)
+	}
+
+	worker := testLog.Named("Worker")
+	process := testLog.Named("Server.Process")
+	client := testLog.Named("Client")
+	clientSub := testLog.Named("Client.Sub")
+
+	worker.InfoContext(ctx, "worker:info")
+	process.DebugContext(ctx, "process:debug")
+	client.DebugContext(ctx, "client:debug")
+	client.InfoContext(ctx, "client:info")
+	clientSub.InfoContext(ctx, "clientsub:info")
+
+	expectLogLines(t, buf, []string{
+		"time=" + timeTestString + " level=DEBUG msg=process:debug",
+		"time=" + timeTestString + " level=INFO msg=client:info",
+		"time=" + timeTestString + " level=INFO msg=clientsub:info",
+	})
+
+	if diff := cmp.Diff(
+		testLog.String(),
+		"Client.Sub:INFO,Client:INFO,Internal.SlogManager:ERROR,Server.Process:DEBUG,Worker:WARN",
+	); diff != "" {
+		t.Errorf("SlogManager: string : -got +want:\n%s", diff)
+	}
+}
+
+func TestSlogManagerWithLevelSpecInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := slogtool.NewSlogManager(
+		context.Background(),
+		slogtool.WithLevelSpec("Server=loud"),
+	)
+	if !errors.Is(err, slogtool.ErrInvalidLevelSpec) {
+		t.Fatalf("expected ErrInvalidLevelSpec, got=%v", err)
+	}
+}
+
+//nolint:paralleltest // uses t.Setenv.
+func TestSlogManagerWithLevelSpecFromEnv(t *testing.T) {
+	t.Setenv(slogtool.DefaultLevelSpecEnv, "debug,Client=error")
+
+	testLog, err := slogtool.NewSlogManager(
+		context.Background(),
+		slogtool.WithWriter(bytes.NewBuffer(nil)),
+		slogtool.WithLevelSpecFromEnv(""),
+	)
+	if err != nil {
+		t.Fatalf("NewSlogManager returned error: %v", err)
+	}
+
+	_ = testLog.Named("Server")
+	_ = testLog.Named("Client.Sub")
+
+	if diff := cmp.Diff(
+		testLog.String(),
+		"Client.Sub:ERROR,Client:ERROR,Internal.SlogManager:ERROR+247,Server:DEBUG",
+	); diff != "" {
+		t.Errorf("SlogManager: string : -got +want:\n%s", diff)
+	}
+}
+
+func TestSlogManagerLevelSpecRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	first, _ := slogtool.NewSlogManager(
+		context.Background(),
+		slogtool.WithWriter(bytes.NewBuffer(nil)),
+		slogtool.WithDefaultLevel(slog.LevelWarn),
+		slogtool.WithLevelSpec("Worker.*=debug"),
+	)
+
+	_ = first.Named("Server")
+	_ = first.Named("Server.Process")
+	_ = first.Named("Worker.One")
+	first.SetLevel("Server", "error")
+
+	spec := first.LevelSpec()
+	if diff := cmp.Diff(
+		spec,
+		"WARN,Worker.*=DEBUG,Internal.SlogManager=ERROR+247,Server=ERROR,Worker.One=DEBUG",
+	); diff != "" {
+		t.Errorf("SlogManager: LevelSpec : -got +want:\n%s", diff)
+	}
+
+	second, err := slogtool.NewSlogManager(
+		context.Background(),
+		slogtool.WithWriter(bytes.NewBuffer(nil)),
+		slogtool.WithLevelSpec(spec),
+	)
+	if err != nil {
+		t.Fatalf("NewSlogManager returned error: %v", err)
+	}
+
+	_ = second.Named("Server.Process")
+	_ = second.Named("Worker.One")
+
+	if diff := cmp.Diff(second.String(), first.String()); diff != "" {
+		t.Errorf("SlogManager: round trip : -got +want:\n%s", diff)
+	}
+	if diff := cmp.Diff(second.LevelSpec(), spec); diff != "" {
+		t.Errorf("SlogManager: round trip spec : -got +want:\n%s", diff)
+	}
+}
+
+func TestSlogManagerLevelSpecRoundTripDefaultRule(t *testing.T) {
+	t.Parallel()
+
+	first, _ := slogtool.NewSlogManager(
+		context.Background(),
+		slogtool.WithWriter(bytes.NewBuffer(nil)),
+	)
+
+	first.SetLevel("*", "warn")
+	first.SetLevel("Worker.*", "debug")
+
+	spec := first.LevelSpec()
+	if diff := cmp.Diff(spec, "WARN,Worker.*=DEBUG,Internal.SlogManager=ERROR+247"); diff != "" {
+		t.Errorf("SlogManager: LevelSpec : -got +want:\n%s", diff)
+	}
+
+	second, err := slogtool.NewSlogManager(
+		context.Background(),
+		slogtool.WithWriter(bytes.NewBuffer(nil)),
+		slogtool.WithLevelSpec(spec),
+	)
+	if err != nil {
+		t.Fatalf("NewSlogManager returned error: %v", err)
+	}
+
+	if diff := cmp.Diff(second.LevelSpec(), spec); diff != "" {
+		t.Errorf("SlogManager: round trip spec : -got +want:\n%s", diff)
+	}
+}
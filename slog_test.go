@@ -13,6 +13,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/na4ma4/go-slogtool"
+	"github.com/na4ma4/go-slogtool/prettylog"
 )
 
 func expectLogLines(t *testing.T, rd io.Reader, expect []string) {
@@ -735,3 +736,68 @@ func TestSlogManagerSubLoggerWith(t *testing.T) {
 		`{"time":"` + timeTestString + `","level":"DEBUG","msg":"sublog:debug4","foo":"bar"}`,
 	})
 }
+
+func TestSlogManagerWithLoggerName(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	ctx := context.Background()
+	testLog, _ := slogtool.NewSlogManager(
+		ctx,
+		slogtool.WithWriter(buf),
+		slogtool.WithDefaultLevel(slog.LevelDebug),
+		slogtool.WithLoggerName(""),
+	)
+
+	testLog.Named("Server.Process").With(slog.String("foo", "bar")).DebugContext(ctx, "sublog:debug1")
+
+	expectLogLines(t, buf, []string{
+		"time=" + timeTestString + " level=DEBUG msg=sublog:debug1 logger=Server.Process foo=bar",
+	})
+}
+
+func TestSlogManagerWithLoggerNameJSONCustomKey(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	ctx := context.Background()
+	testLog, _ := slogtool.NewSlogManager(
+		ctx,
+		slogtool.WithWriter(buf),
+		slogtool.WithDefaultLevel(slog.LevelDebug),
+		slogtool.WithJSONHandler(),
+		slogtool.WithLoggerName("component"),
+	)
+
+	testLog.Named("sublog").DebugContext(ctx, "sublog:debug1")
+
+	expectLogLines(t, buf, []string{
+		`{"time":"` + timeTestString + `","level":"DEBUG","msg":"sublog:debug1","component":"sublog"}`,
+	})
+}
+
+func TestSlogManagerWithLoggerNamePrettylog(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	ctx := context.Background()
+	testLog, _ := slogtool.NewSlogManager(
+		ctx,
+		slogtool.WithWriter(buf),
+		slogtool.WithDefaultLevel(slog.LevelDebug),
+		slogtool.WithCustomHandler(func(_ string, w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+			return prettylog.NewHandler(w, opts)
+		}),
+		slogtool.WithLoggerName(""),
+	)
+
+	testLog.Named("Server.Process").DebugContext(ctx, "sublog:debug1")
+
+	out := buf.String()
+	if !strings.Contains(out, prettylog.Colorize(prettylog.LightGreen, "[Server.Process]")+" ") {
+		t.Errorf("expected coloured logger name prefix, got=%q", out)
+	}
+	if strings.Contains(out, `"logger"`) {
+		t.Errorf("expected logger name not to be added as an attribute, got=%q", out)
+	}
+}
@@ -0,0 +1,221 @@
+package slogtool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	contextKeyTrace contextKey = "trace"
+
+	traceIDLength     = 32
+	b3TraceIDLength   = 16
+	spanIDLength      = 16
+	traceFlagsLength  = 2
+	traceparentFields = 4
+	traceFlagSampled  = "01"
+	traceFlagNone     = "00"
+
+	// traceFlagSampledBit is the sampled bit of the W3C trace-flags bit field.
+	traceFlagSampledBit = 0x01
+)
+
+// ErrInvalidTraceContext is returned when a trace context header can not be parsed.
+var ErrInvalidTraceContext = errors.New("invalid trace context")
+
+// TraceContext is the trace and span ids of a request, parsed from the W3C `traceparent` and
+// `tracestate` headers or the B3 headers.
+type TraceContext struct {
+	TraceID    string
+	SpanID     string
+	TraceFlags string
+	TraceState string
+}
+
+// Sampled returns true if the sampled bit of the trace flags is set, the other bits (e.g. the
+// random flag) are ignored.
+func (tc TraceContext) Sampled() bool {
+	flags, err := strconv.ParseUint(tc.TraceFlags, 16, 8)
+
+	return err == nil && flags&traceFlagSampledBit != 0
+}
+
+// Attrs returns the `trace_id`, `span_id` and `trace_flags` attributes.
+func (tc TraceContext) Attrs() []slog.Attr {
+	return []slog.Attr{
+		slog.String("trace_id", tc.TraceID),
+		slog.String("span_id", tc.SpanID),
+		slog.String("trace_flags", tc.TraceFlags),
+	}
+}
+
+// ParseTraceparent parses a W3C `traceparent` header value, e.g.
+// `00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01`.
+func ParseTraceparent(value string) (TraceContext, error) {
+	fields := strings.Split(strings.TrimSpace(value), "-")
+	if len(fields) < traceparentFields {
+		return TraceContext{}, fmt.Errorf("%w: traceparent: %q", ErrInvalidTraceContext, value)
+	}
+
+	version, traceID, spanID, flags := fields[0], fields[1], fields[2], fields[3]
+
+	switch {
+	case !isLowerHex(version, traceFlagsLength) || version == "ff",
+		version == "00" && len(fields) != traceparentFields,
+		!isLowerHex(traceID, traceIDLength) || isZeroHex(traceID),
+		!isLowerHex(spanID, spanIDLength) || isZeroHex(spanID),
+		!isLowerHex(flags, traceFlagsLength):
+		return TraceContext{}, fmt.Errorf("%w: traceparent: %q", ErrInvalidTraceContext, value)
+	}
+
+	return TraceContext{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: flags,
+	}, nil
+}
+
+// ParseB3 parses the single `b3` header or the multiple `X-B3-*` headers, a 64-bit trace id is
+// left-padded to 128 bits.
+func ParseB3(header http.Header) (TraceContext, error) {
+	if single := header.Get("b3"); single != "" {
+		return parseB3Single(single)
+	}
+
+	traceID := strings.ToLower(header.Get("X-B3-TraceId"))
+	spanID := strings.ToLower(header.Get("X-B3-SpanId"))
+
+	sampled := header.Get("X-B3-Sampled")
+	if header.Get("X-B3-Flags") == "1" {
+		sampled = "d"
+	}
+
+	return newB3TraceContext(traceID, spanID, sampled)
+}
+
+// parseB3Single parses the single `b3` header, e.g. `{TraceId}-{SpanId}-{SamplingState}-{ParentSpanId}`.
+func parseB3Single(value string) (TraceContext, error) {
+	fields := strings.Split(strings.ToLower(strings.TrimSpace(value)), "-")
+	if len(fields) < 2 { //nolint:mnd // trace and span id.
+		return TraceContext{}, fmt.Errorf("%w: b3: %q", ErrInvalidTraceContext, value)
+	}
+
+	sampled := ""
+	if len(fields) > 2 { //nolint:mnd // sampling state.
+		sampled = fields[2]
+	}
+
+	return newB3TraceContext(fields[0], fields[1], sampled)
+}
+
+func newB3TraceContext(traceID, spanID, sampled string) (TraceContext, error) {
+	if len(traceID) == b3TraceIDLength {
+		traceID = strings.Repeat("0", traceIDLength-b3TraceIDLength) + traceID
+	}
+
+	if !isLowerHex(traceID, traceIDLength) || isZeroHex(traceID) ||
+		!isLowerHex(spanID, spanIDLength) || isZeroHex(spanID) {
+		return TraceContext{}, fmt.Errorf("%w: b3: trace id %q, span id %q", ErrInvalidTraceContext, traceID, spanID)
+	}
+
+	flags := traceFlagNone
+	if sampled == "1" || sampled == "d" || sampled == "true" {
+		flags = traceFlagSampled
+	}
+
+	return TraceContext{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: flags,
+	}, nil
+}
+
+// TraceContextFromRequest parses the trace context from the W3C `traceparent` and `tracestate` headers,
+// falling back to the B3 headers.
+func TraceContextFromRequest(req *http.Request) (TraceContext, bool) {
+	if tp := req.Header.Get("traceparent"); tp != "" {
+		if tc, err := ParseTraceparent(tp); err == nil {
+			tc.TraceState = req.Header.Get("tracestate")
+			return tc, true
+		}
+	}
+
+	if tc, err := ParseB3(req.Header); err == nil {
+		return tc, true
+	}
+
+	return TraceContext{}, false
+}
+
+// ContextWithTraceContext returns a copy of ctx with the trace context stored in it.
+func ContextWithTraceContext(ctx context.Context, tc TraceContext) context.Context {
+	return context.WithValue(ctx, contextKeyTrace, tc)
+}
+
+// TraceContextFromContext retrieves the trace context stored by [ContextWithTraceContext] or the HTTP
+// logging handler from the provided context.
+func TraceContextFromContext(ctx context.Context) (TraceContext, bool) {
+	tc, ok := ctx.Value(contextKeyTrace).(TraceContext)
+
+	return tc, ok
+}
+
+// TraceHandler is a [slog.Handler] that adds the `trace_id`, `span_id` and `trace_flags` attributes of
+// the trace context stored in the context to each record.
+type TraceHandler struct {
+	handler slog.Handler
+}
+
+// NewTraceHandler returns a new TraceHandler wrapping handler.
+func NewTraceHandler(handler slog.Handler) *TraceHandler {
+	return &TraceHandler{handler: handler}
+}
+
+// Enabled reports whether the wrapped handler handles records at the given level.
+func (h *TraceHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle adds the trace context attributes to the record and passes it to the wrapped handler.
+func (h *TraceHandler) Handle(ctx context.Context, r slog.Record) error {
+	if tc, ok := TraceContextFromContext(ctx); ok {
+		r = r.Clone()
+		r.AddAttrs(tc.Attrs()...)
+	}
+
+	return h.handler.Handle(ctx, r)
+}
+
+// WithAttrs returns a new TraceHandler wrapping the handler returned by the wrapped handler's WithAttrs.
+func (h *TraceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &TraceHandler{handler: h.handler.WithAttrs(attrs)}
+}
+
+// WithGroup returns a new TraceHandler wrapping the handler returned by the wrapped handler's WithGroup.
+func (h *TraceHandler) WithGroup(name string) slog.Handler {
+	return &TraceHandler{handler: h.handler.WithGroup(name)}
+}
+
+func isLowerHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+
+	for i := range len(s) {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isZeroHex(s string) bool {
+	return strings.Trim(s, "0") == ""
+}
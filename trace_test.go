@@ -0,0 +1,236 @@
+package slogtool_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/na4ma4/go-slogtool"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		value  string
+		expect slogtool.TraceContext
+		err    bool
+	}{
+		{
+			"valid", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			slogtool.TraceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", TraceFlags: "01"},
+			false,
+		},
+		{
+			"future-version", "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-extra",
+			slogtool.TraceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", TraceFlags: "00"},
+			false,
+		},
+		{"version-ff", "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", slogtool.TraceContext{}, true},
+		{"version-00-extra", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", slogtool.TraceContext{}, true},
+		{"zero-trace-id", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", slogtool.TraceContext{}, true},
+		{"zero-span-id", "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", slogtool.TraceContext{}, true},
+		{"uppercase", "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", slogtool.TraceContext{}, true},
+		{"short", "00-4bf92f3577b34da6-00f067aa0ba902b7-01", slogtool.TraceContext{}, true},
+		{"empty", "", slogtool.TraceContext{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := slogtool.ParseTraceparent(tt.value)
+			if tt.err {
+				if !errors.Is(err, slogtool.ErrInvalidTraceContext) {
+					t.Errorf("ParseTraceparent: got error '%v', want '%v'", err, slogtool.ErrInvalidTraceContext)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseTraceparent returned error: %v", err)
+			}
+
+			if diff := cmp.Diff(got, tt.expect); diff != "" {
+				t.Errorf("ParseTraceparent: -got +want:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTraceContextSampled(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		flags   string
+		sampled bool
+	}{
+		{"00", false},
+		{"01", true},
+		{"02", false},
+		{"03", true},
+		{"ff", true},
+		{"", false},
+		{"zz", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.flags, func(t *testing.T) {
+			t.Parallel()
+
+			if got := (slogtool.TraceContext{TraceFlags: tt.flags}).Sampled(); got != tt.sampled {
+				t.Errorf("Sampled() mismatch: got=%t want=%t", got, tt.sampled)
+			}
+		})
+	}
+}
+
+func TestParseB3(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		expect slogtool.TraceContext
+		err    bool
+	}{
+		{
+			"single",
+			map[string]string{"b3": "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1-05e3ac9a4f6e3b90"},
+			slogtool.TraceContext{TraceID: "80f198ee56343ba864fe8b2a57d3eff7", SpanID: "e457b5a2e4d86bd1", TraceFlags: "01"},
+			false,
+		},
+		{
+			"single-64bit-deferred",
+			map[string]string{"b3": "a3ce929d0e0e4736-e457b5a2e4d86bd1"},
+			slogtool.TraceContext{TraceID: "0000000000000000a3ce929d0e0e4736", SpanID: "e457b5a2e4d86bd1", TraceFlags: "00"},
+			false,
+		},
+		{
+			"multi",
+			map[string]string{
+				"X-B3-TraceId": "80f198ee56343ba864fe8b2a57d3eff7",
+				"X-B3-SpanId":  "e457b5a2e4d86bd1",
+				"X-B3-Sampled": "0",
+			},
+			slogtool.TraceContext{TraceID: "80f198ee56343ba864fe8b2a57d3eff7", SpanID: "e457b5a2e4d86bd1", TraceFlags: "00"},
+			false,
+		},
+		{
+			"multi-debug",
+			map[string]string{
+				"X-B3-TraceId": "80f198ee56343ba864fe8b2a57d3eff7",
+				"X-B3-SpanId":  "e457b5a2e4d86bd1",
+				"X-B3-Flags":   "1",
+			},
+			slogtool.TraceContext{TraceID: "80f198ee56343ba864fe8b2a57d3eff7", SpanID: "e457b5a2e4d86bd1", TraceFlags: "01"},
+			false,
+		},
+		{"single-deny", map[string]string{"b3": "0"}, slogtool.TraceContext{}, true},
+		{"missing", map[string]string{}, slogtool.TraceContext{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			header := http.Header{}
+			for k, v := range tt.header {
+				header.Set(k, v)
+			}
+
+			got, err := slogtool.ParseB3(header)
+			if tt.err {
+				if !errors.Is(err, slogtool.ErrInvalidTraceContext) {
+					t.Errorf("ParseB3: got error '%v', want '%v'", err, slogtool.ErrInvalidTraceContext)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseB3 returned error: %v", err)
+			}
+
+			if diff := cmp.Diff(got, tt.expect); diff != "" {
+				t.Errorf("ParseB3: -got +want:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoggingHTTPHandlerTraceContext(t *testing.T) {
+	t.Parallel()
+
+	accessBuf := bytes.NewBuffer(nil)
+	appBuf := bytes.NewBuffer(nil)
+	appLogger := slog.New(slogtool.NewTraceHandler(slog.NewTextHandler(appBuf, nil)))
+
+	h := slogtool.LoggingHTTPHandler(
+		slog.New(slog.NewJSONHandler(accessBuf, nil)),
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			appLogger.InfoContext(r.Context(), "inner")
+			appLogger.Info("no context")
+			_, _ = w.Write([]byte("ok"))
+		}),
+		slogtool.LoggingOptionTiming(false),
+		slogtool.LoggingOptionTimestamp(false),
+		slogtool.LoggingOptionTraceContext(true),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/test", nil)
+	req.RemoteAddr = "127.0.0.1:1234"
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req.Header.Set("tracestate", "vendor=value")
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	obj := readSingleLogObject(t, accessBuf)
+	httpObj, _ := obj["http"].(map[string]any)
+
+	for k, want := range map[string]string{
+		"trace_id":    "4bf92f3577b34da6a3ce929d0e0e4736",
+		"span_id":     "00f067aa0ba902b7",
+		"trace_flags": "01",
+	} {
+		if got, _ := httpObj[k].(string); got != want {
+			t.Errorf("http.%s mismatch: got=%v want=%q", k, httpObj[k], want)
+		}
+	}
+
+	expectLogLines(t, appBuf, []string{
+		"time=" + timeTestString + " level=INFO msg=inner trace_id=4bf92f3577b34da6a3ce929d0e0e4736 " +
+			"span_id=00f067aa0ba902b7 trace_flags=01",
+		"time=" + timeTestString + " level=INFO msg=\"no context\"",
+	})
+}
+
+func TestSlogManagerWithTraceContext(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	testLog, _ := slogtool.NewSlogManager(
+		context.Background(),
+		slogtool.WithWriter(buf),
+		slogtool.WithTraceContext(),
+	)
+
+	ctx := slogtool.ContextWithTraceContext(context.Background(), slogtool.TraceContext{
+		TraceID:    "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:     "00f067aa0ba902b7",
+		TraceFlags: "00",
+	})
+
+	testLog.Named("Server").With(slog.String("attr", "value")).InfoContext(ctx, "msg")
+
+	expectLogLines(t, buf, []string{
+		"time=" + timeTestString + " level=INFO msg=msg attr=value trace_id=4bf92f3577b34da6a3ce929d0e0e4736 " +
+			"span_id=00f067aa0ba902b7 trace_flags=00",
+	})
+}